@@ -0,0 +1,208 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+
+	"github.com/javiercepeda/tapas/internal/ports"
+)
+
+// ExitViolations is returned by tapas audit when a finding reaches --fail-on.
+const ExitViolations = 3
+
+// AuditSchema identifies the tapas audit --format json document.
+const AuditSchema = "tapas/audit/v1"
+
+// auditReport is the JSON output of tapas audit.
+type auditReport struct {
+	Schema   string          `json:"schema"`
+	Findings []ports.Finding `json:"findings"`
+}
+
+// runAudit implements "tapas audit": check the current listeners against the exposure rules.
+func runAudit(env Env, args []string) int {
+	fs := newFlagSet("audit", "[--format text|json|sarif] [--fail-on error|warning|never] [--ignore RULE,...]")
+	format := fs.String("format", "text", "output format: text, json or sarif")
+	failOn := fs.String("fail-on", "warning", "exit non-zero on findings of at least this severity: error, warning, or never")
+	var ignore stringList
+	fs.Var(&ignore, "ignore", "rule ID to skip (repeatable, or comma-separated); adds to exposure.ignore")
+	usage := fs.Usage
+	fs.Usage = func() {
+		usage()
+		fmt.Fprintln(os.Stderr, "\nRules:")
+		for _, r := range ports.ExposureRules {
+			fmt.Fprintf(os.Stderr, "  %-22s %-8s %s\n", r.ID, r.Severity, r.Title)
+		}
+	}
+	if code, ok := parseFlags(fs, args); !ok {
+		return code
+	}
+	if fs.NArg() > 0 {
+		fs.Usage()
+		return ExitUsage
+	}
+	switch *failOn {
+	case "error", "warning", "never":
+	default:
+		errorf("audit", "--fail-on must be error, warning or never")
+		return ExitUsage
+	}
+	opts := ports.ExposureOptions{
+		Allow:  ports.PolicyRules(env.Config.Exposure.Allow),
+		Ignore: append(append([]string(nil), env.Config.Exposure.Ignore...), ignore...),
+	}
+	for _, id := range opts.Ignore {
+		if _, ok := ports.LookupExposureRule(id); !ok {
+			errorf("audit", "unknown rule %q", id)
+			return ExitUsage
+		}
+	}
+	if err := ports.ValidatePolicy(ports.Policy{Protected: opts.Allow}); err != nil {
+		return errorf("audit", "exposure.allow: %v", err)
+	}
+
+	list, err := env.Lister.List()
+	if err != nil {
+		return errorf("audit", "%v", err)
+	}
+	findings := ports.AuditExposure(list, opts)
+	switch *format {
+	case "text":
+		err = writeAuditText(os.Stdout, findings)
+	case "json":
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		err = enc.Encode(auditReport{Schema: AuditSchema, Findings: findings})
+	case "sarif":
+		err = writeSARIF(os.Stdout, findings)
+	default:
+		errorf("audit", "unknown format %q (use text, json or sarif)", *format)
+		return ExitUsage
+	}
+	if err != nil {
+		return errorf("audit", "%v", err)
+	}
+	for _, f := range findings {
+		if *failOn == "warning" || (*failOn == "error" && f.Severity == ports.SeverityError) {
+			return ExitViolations
+		}
+	}
+	return ExitOK
+}
+
+func writeAuditText(w io.Writer, findings []ports.Finding) error {
+	if len(findings) == 0 {
+		_, err := fmt.Fprintln(w, "No exposure findings.")
+		return err
+	}
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "SEVERITY\tRULE\tPORT\tPID\tFINDING")
+	nErrors := 0
+	for _, f := range findings {
+		if f.Severity == ports.SeverityError {
+			nErrors++
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%d\t%s\t%s\n", f.Severity, f.Rule, f.Listener.Port, pidLabel(f.Listener.PID), f.Message)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	shown := make(map[ports.Remediation]bool) // one listener can break several rules
+	for _, f := range findings {
+		if f.Fix == nil || shown[*f.Fix] {
+			continue
+		}
+		shown[*f.Fix] = true
+		fmt.Fprintf(w, "\nPort %d: %s\n  seen: %s\n  fix:  %s\n", f.Listener.Port, f.Fix.How, f.Fix.Seen, f.Fix.Fix)
+	}
+	_, err := fmt.Fprintf(w, "\n%d finding(s), %d error(s). Rules: tapas audit -h; allowlist and ignores: exposure in the config.\n", len(findings), nErrors)
+	return err
+}
+
+// SARIF 2.1.0, the subset code-scanning tools read: one run, the rules, and a result per
+// finding. Listeners have no source file, so results carry a logical location ("port 5432").
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID                   string       `json:"id"`
+	ShortDescription     sarifMessage `json:"shortDescription"`
+	Help                 sarifMessage `json:"help"`
+	DefaultConfiguration struct {
+		Level string `json:"level"`
+	} `json:"defaultConfiguration"`
+}
+
+type sarifResult struct {
+	RuleID              string            `json:"ruleId"`
+	Level               string            `json:"level"`
+	Message             sarifMessage      `json:"message"`
+	Locations           []sarifLocation   `json:"locations"`
+	PartialFingerprints map[string]string `json:"partialFingerprints"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifLocation struct {
+	LogicalLocations []sarifLogicalLocation `json:"logicalLocations"`
+}
+
+type sarifLogicalLocation struct {
+	Name string `json:"name"`
+	Kind string `json:"kind"`
+}
+
+func writeSARIF(w io.Writer, findings []ports.Finding) error {
+	driver := sarifDriver{Name: "tapas", InformationURI: "https://github.com/javiercepeda/tapas", Rules: []sarifRule{}}
+	for _, r := range ports.ExposureRules {
+		rule := sarifRule{ID: r.ID, ShortDescription: sarifMessage{r.Title}, Help: sarifMessage{r.Help}}
+		rule.DefaultConfiguration.Level = string(r.Severity)
+		driver.Rules = append(driver.Rules, rule)
+	}
+	results := []sarifResult{}
+	for _, f := range findings {
+		msg := f.Message
+		if f.Fix != nil {
+			msg += ". To fix, " + f.Fix.How + ": " + f.Fix.Fix
+		}
+		results = append(results, sarifResult{
+			RuleID:    f.Rule,
+			Level:     string(f.Severity),
+			Message:   sarifMessage{msg},
+			Locations: []sarifLocation{{LogicalLocations: []sarifLogicalLocation{{Name: fmt.Sprintf("port %d", f.Listener.Port), Kind: "resource"}}}},
+			// Stable across restarts (no PID), so code scanning tracks a finding instead of reopening it.
+			PartialFingerprints: map[string]string{"listener/v1": fmt.Sprintf("%d/%s", f.Listener.Port, f.Listener.Process)},
+		})
+	}
+	doc := sarifLog{
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Version: "2.1.0",
+		Runs:    []sarifRun{{Tool: sarifTool{Driver: driver}, Results: results}},
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(doc)
+}
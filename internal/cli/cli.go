@@ -0,0 +1,115 @@
+// Package cli implements the tapas subcommands (tapas log, ...). Running tapas without a
+// subcommand starts the TUI. Like the UI, commands go through internal/ports for everything
+// that touches the system.
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/javiercepeda/tapas/internal/config"
+	"github.com/javiercepeda/tapas/internal/ports"
+)
+
+// Exit codes shared by every subcommand.
+const (
+	ExitOK    = 0
+	ExitError = 1 // the command ran and failed
+	ExitUsage = 2 // bad flags or arguments
+)
+
+// Env is what every subcommand runs with: the user config, where state lives, and the port lister.
+type Env struct {
+	Config   config.Config
+	StateDir string // "" when unknown: commands that need state degrade or fail clearly
+	Lister   ports.Lister
+}
+
+// Command is a tapas subcommand.
+type Command struct {
+	Name    string
+	Summary string
+	Run     func(env Env, args []string) int
+}
+
+// Commands returns every subcommand, in help order.
+func Commands() []Command {
+	return []Command{
+		{"audit", "Check listeners for risky exposure (public databases, debug ports, ...); text, JSON or SARIF", runAudit},
+		{"diff", "Compare two snapshots (tapas list -o json), or one with the current listeners", runDiff},
+		{"export", "Write the listeners as a JSON snapshot or OpenMetrics gauges, atomically (for cron)", runExport},
+		{"free", "Make sure ports are free: terminate, escalate to SIGKILL, wait for release", runFree},
+		{"history", "Show when listeners on a port or project were seen, restarted and peaked", runHistory},
+		{"kill", "Signal whatever listens on the given ports", runKill},
+		{"list", "Print listening ports as a table, JSON, CSV or a template", runList},
+		{"log", "Show and filter the audit log of signals, container actions and relaunches", runLog},
+		{"pick", "Choose ports in the UI and print their port, PID, URL, ... to stdout", runPick},
+		{"port", "Suggest a free port for a project (tapas port suggest), stable across runs", runPort},
+		{"prune", "Gracefully terminate idle, old or orphaned dev servers", runPrune},
+		{"signal", "Signal a verified process (helper the UI runs through sudo)", runSignal},
+		{"wait", "Wait until a port is listening (and answers HTTP) or free", runWait},
+		{"why", "Explain what holds a port, where it came from and how to stop it", runWhy},
+	}
+}
+
+// Lookup returns the subcommand called name.
+func Lookup(name string) (Command, bool) {
+	for _, c := range Commands() {
+		if c.Name == name {
+			return c, true
+		}
+	}
+	return Command{}, false
+}
+
+// PrintCommands lists the subcommands for the top-level usage text.
+func PrintCommands(w io.Writer) {
+	for _, c := range Commands() {
+		fmt.Fprintf(w, "  %-10s %s\n", c.Name, c.Summary)
+	}
+}
+
+// newFlagSet returns a flag set whose usage line names the subcommand.
+func newFlagSet(name, args string) *flag.FlagSet {
+	fs := flag.NewFlagSet("tapas "+name, flag.ContinueOnError)
+	fs.SetOutput(os.Stderr)
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: tapas %s %s\n\n", name, args)
+		fs.PrintDefaults()
+	}
+	return fs
+}
+
+// parseFlags parses args and reports the exit code to use when parsing failed (-h is not an error).
+func parseFlags(fs *flag.FlagSet, args []string) (int, bool) {
+	if err := fs.Parse(args); err != nil {
+		if err == flag.ErrHelp {
+			return ExitOK, false
+		}
+		return ExitUsage, false
+	}
+	return ExitOK, true
+}
+
+// stringList is a repeatable flag that also splits comma-separated values.
+type stringList []string
+
+func (l *stringList) String() string { return strings.Join(*l, ",") }
+
+func (l *stringList) Set(v string) error {
+	for _, part := range strings.Split(v, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			*l = append(*l, part)
+		}
+	}
+	return nil
+}
+
+// errorf prints "tapas <cmd>: ..." to stderr and returns ExitError.
+func errorf(cmd, format string, args ...any) int {
+	fmt.Fprintf(os.Stderr, "tapas %s: %s\n", cmd, fmt.Sprintf(format, args...))
+	return ExitError
+}
@@ -0,0 +1,149 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/javiercepeda/tapas/internal/ports"
+)
+
+// ExitDiffers is returned by tapas diff when the snapshots differ (like diff(1), but 1 is taken by errors).
+const ExitDiffers = 3
+
+// diffReport is the JSON output of tapas diff.
+type diffReport struct {
+	Schema string         `json:"schema"`
+	Before snapshotSource `json:"before"`
+	After  snapshotSource `json:"after"`
+	ports.SnapshotDiff
+}
+
+// snapshotSource says where one side of a diff came from.
+type snapshotSource struct {
+	Source      string    `json:"source"` // file name, "-" for stdin, or "live"
+	Host        string    `json:"host,omitempty"`
+	GeneratedAt time.Time `json:"generated_at,omitempty"`
+}
+
+// runDiff implements "tapas diff": compare two snapshots, or one snapshot with the current listeners.
+func runDiff(env Env, args []string) int {
+	fs := newFlagSet("diff", "[--json] BEFORE.json [AFTER.json]  (- reads stdin; without AFTER, compare with the current listeners)")
+	asJSON := fs.Bool("json", false, "print the differences as JSON")
+	if code, ok := parseFlags(fs, args); !ok {
+		return code
+	}
+	if fs.NArg() < 1 || fs.NArg() > 2 {
+		fs.Usage()
+		return ExitUsage
+	}
+	before, err := loadSnapshot(fs.Arg(0))
+	if err != nil {
+		return errorf("diff", "%s: %v", fs.Arg(0), err)
+	}
+	var after ports.Snapshot
+	afterSource := "live"
+	if fs.NArg() == 2 {
+		afterSource = fs.Arg(1)
+		if after, err = loadSnapshot(afterSource); err != nil {
+			return errorf("diff", "%s: %v", afterSource, err)
+		}
+	} else {
+		list, err := env.Lister.List()
+		if err != nil {
+			return errorf("diff", "%v", err)
+		}
+		after = ports.NewSnapshot(list)
+	}
+	d := ports.DiffSnapshots(before, after)
+	if *asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		report := diffReport{
+			Schema:       ports.DiffSchema,
+			Before:       snapshotSource{fs.Arg(0), before.Host, before.GeneratedAt},
+			After:        snapshotSource{afterSource, after.Host, after.GeneratedAt},
+			SnapshotDiff: d,
+		}
+		if err := enc.Encode(report); err != nil {
+			return errorf("diff", "%v", err)
+		}
+	} else if err := writeDiff(os.Stdout, fs.Arg(0), before, afterSource, after, d); err != nil {
+		return errorf("diff", "%v", err)
+	}
+	if d.Empty() {
+		return ExitOK
+	}
+	return ExitDiffers
+}
+
+func loadSnapshot(name string) (ports.Snapshot, error) {
+	if name == "-" {
+		return ports.ReadSnapshot(os.Stdin)
+	}
+	f, err := os.Open(name)
+	if err != nil {
+		return ports.Snapshot{}, err
+	}
+	defer f.Close()
+	return ports.ReadSnapshot(f)
+}
+
+// writeDiff prints one line per difference: + added, - removed, > moved, ~ changed.
+func writeDiff(w io.Writer, beforeSource string, before ports.Snapshot, afterSource string, after ports.Snapshot, d ports.SnapshotDiff) error {
+	fmt.Fprintf(w, "--- %s\n+++ %s\n\n", describeSnapshot(beforeSource, before), describeSnapshot(afterSource, after))
+	if d.Empty() {
+		_, err := fmt.Fprintln(w, "No differences.")
+		return err
+	}
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	line := func(mark string, r ports.Record, details string) {
+		fmt.Fprintf(tw, "%s %d/%s\t%s\t%s\t%s\n", mark, r.Port, r.Protocol, recordLabel(r), ports.OrDash(r.Project), details)
+	}
+	for _, r := range d.Added {
+		line("+", r, r.Bind)
+	}
+	for _, r := range d.Removed {
+		line("-", r, r.Bind)
+	}
+	for _, c := range d.Moved {
+		line(">", c.Before, strings.Join(c.Changes, "; "))
+	}
+	for _, c := range d.Changed {
+		line("~", c.Before, strings.Join(c.Changes, "; "))
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	_, err := fmt.Fprintf(w, "\n%d added, %d removed, %d moved, %d changed.\n", len(d.Added), len(d.Removed), len(d.Moved), len(d.Changed))
+	return err
+}
+
+func describeSnapshot(source string, s ports.Snapshot) string {
+	if source == "live" {
+		source = "current listeners"
+	}
+	var about []string
+	if s.Host != "" {
+		about = append(about, s.Host)
+	}
+	if !s.GeneratedAt.IsZero() {
+		about = append(about, s.GeneratedAt.Local().Format("2006-01-02 15:04"))
+	}
+	if len(about) == 0 {
+		return source
+	}
+	return fmt.Sprintf("%s (%s)", source, strings.Join(about, ", "))
+}
+
+// recordLabel is the process column of a record; bare records (jq output) may lack the label.
+func recordLabel(r ports.Record) string {
+	if r.Label != "" {
+		return r.Label
+	}
+	return ports.OrDash(r.Process)
+}
@@ -0,0 +1,51 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+
+	"github.com/javiercepeda/tapas/internal/ports"
+)
+
+// runExport implements "tapas export": the current listeners as a JSON snapshot or OpenMetrics
+// gauges, on stdout or written atomically to a file (for cron and node_exporter's textfile collector).
+func runExport(env Env, args []string) int {
+	fs := newFlagSet("export", "[--format json|openmetrics] [-o PATH]")
+	format := fs.String("format", "json", "json (the tapas list -o json snapshot) or openmetrics")
+	out := fs.String("o", "", "write to this file via a temp file and rename (mode 0644) instead of stdout")
+	if code, ok := parseFlags(fs, args); !ok {
+		return code
+	}
+	if fs.NArg() > 0 {
+		fs.Usage()
+		return ExitUsage
+	}
+	var write func(buf *bytes.Buffer, list []ports.Port) error
+	switch *format {
+	case "json":
+		write = func(buf *bytes.Buffer, list []ports.Port) error { return ports.WriteJSON(buf, list) }
+	case "openmetrics":
+		write = func(buf *bytes.Buffer, list []ports.Port) error { return ports.WriteOpenMetrics(buf, list) }
+	default:
+		errorf("export", "unknown format %q (use json or openmetrics)", *format)
+		return ExitUsage
+	}
+	list, err := env.Lister.List()
+	if err != nil {
+		return errorf("export", "%v", err)
+	}
+	var buf bytes.Buffer
+	if err := write(&buf, list); err != nil {
+		return errorf("export", "%v", err)
+	}
+	if *out == "" {
+		if _, err := os.Stdout.Write(buf.Bytes()); err != nil {
+			return errorf("export", "%v", err)
+		}
+		return ExitOK
+	}
+	if err := ports.WriteFileAtomic(*out, buf.Bytes(), 0o644); err != nil {
+		return errorf("export", "%v", err)
+	}
+	return ExitOK
+}
@@ -0,0 +1,94 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/javiercepeda/tapas/internal/ports"
+)
+
+// runHistory implements "tapas history": the recorded timeline of a port, project or process.
+func runHistory(env Env, args []string) int {
+	fs := newFlagSet("history", "[--since T | --at T] [-n N] [--json] [PORT|PROJECT|PROCESS]")
+	since := fs.String("since", "", "only events newer than a duration (24h) or date (2006-01-02)")
+	at := fs.String("at", "", "instead of events, show what was listening at a time (a duration ago like 1h, or a date/time); listeners whose first-seen event was rotated out are missing")
+	last := fs.Int("n", 0, "show only the last N matching events")
+	asJSON := fs.Bool("json", false, "print matching events as JSON lines")
+	if code, ok := parseFlags(fs, args); !ok {
+		return code
+	}
+	if fs.NArg() > 1 {
+		fs.Usage()
+		return ExitUsage
+	}
+	if *since != "" && *at != "" {
+		// --since would filter on when each listener started, dropping long-running ones.
+		errorf("history", "--since and --at cannot be combined")
+		return ExitUsage
+	}
+	var q ports.HistoryQuery
+	if fs.NArg() == 1 {
+		arg := fs.Arg(0)
+		if n, err := strconv.ParseUint(strings.TrimPrefix(arg, ":"), 10, 16); err == nil && n > 0 {
+			q.Port = uint16(n)
+		} else {
+			q.Name = arg
+		}
+	}
+	if *since != "" {
+		t, err := parseSince(*since)
+		if err != nil {
+			errorf("history", "--since: %v", err)
+			return ExitUsage
+		}
+		q.Since = t
+	}
+	if ports.HistoryLogPath() == "" {
+		return errorf("history", "history is off; set \"history\": {\"enabled\": true} in the config")
+	}
+	events, err := ports.ReadHistory()
+	if err != nil {
+		return errorf("history", "%v", err)
+	}
+	if *at != "" {
+		t, err := parseSince(*at)
+		if err != nil {
+			errorf("history", "--at: %v", err)
+			return ExitUsage
+		}
+		events = ports.ListenersAt(events, t)
+	}
+	var matched []ports.HistoryEvent
+	for _, e := range events {
+		if q.Match(e) {
+			matched = append(matched, e)
+		}
+	}
+	if *last > 0 && len(matched) > *last {
+		matched = matched[len(matched)-*last:]
+	}
+	if *asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		for _, e := range matched {
+			if err := enc.Encode(e); err != nil {
+				return errorf("history", "%v", err)
+			}
+		}
+		return ExitOK
+	}
+	if len(matched) == 0 {
+		fmt.Fprintln(os.Stderr, "No matching history. Events are recorded whenever ports are listed (UI, tapas list, tapas prune).")
+		return ExitOK
+	}
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "TIME\tEVENT\tPORT\tPID\tPROCESS\tPROJECT\tDETAILS")
+	for _, e := range matched {
+		fmt.Fprintf(tw, "%s\t%s\t%d\t%d\t%s\t%s\t%s\n", e.Time.Local().Format("2006-01-02 15:04:05"),
+			e.Event, e.Port, e.PID, ports.OrDash(e.Process), ports.OrDash(e.Project), e.Details())
+	}
+	return flushOrFail(tw, "history")
+}
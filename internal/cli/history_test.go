@@ -0,0 +1,9 @@
+package cli
+
+import "testing"
+
+func TestRunHistoryAtWithSince(t *testing.T) {
+	if got := runHistory(Env{}, []string{"--at", "1h", "--since", "2h"}); got != ExitUsage {
+		t.Errorf("history --at with --since: exit %d, want %d", got, ExitUsage)
+	}
+}
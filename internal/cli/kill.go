@@ -0,0 +1,277 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/javiercepeda/tapas/internal/ports"
+)
+
+// Exit codes of tapas kill and tapas free, so scripts can tell outcomes apart. With several
+// ports the most severe outcome wins: still bound, then denied, then freed, then already free.
+const (
+	ExitFreed       = ExitOK // every listener exited (and, when waiting, every port is free)
+	ExitAlreadyFree = 3      // nothing was listening on any of the ports
+	ExitDenied      = 4      // refused by policy or --only-if-project, or the signal was not permitted (EPERM)
+	ExitStillBound  = 5      // a signal failed, or a port is still bound after --timeout
+)
+
+// portOutcome is what happened to one requested port, in increasing severity.
+type portOutcome int
+
+const (
+	outcomeAlreadyFree portOutcome = iota
+	outcomeFreed
+	outcomeDenied
+	outcomeStillBound
+)
+
+func (o portOutcome) exitCode() int {
+	switch o {
+	case outcomeAlreadyFree:
+		return ExitAlreadyFree
+	case outcomeDenied:
+		return ExitDenied
+	case outcomeStillBound:
+		return ExitStillBound
+	}
+	return ExitFreed
+}
+
+// killOptions are the flags shared by tapas kill and tapas free.
+type killOptions struct {
+	sig         syscall.Signal
+	terminate   ports.TerminateOptions
+	waitFree    bool
+	onlyProject string
+	quiet       bool
+}
+
+// runKill implements "tapas kill": signal whatever listens on the given ports, by default
+// SIGTERM with the configured grace period, and report whether the processes exited.
+func runKill(env Env, args []string) int {
+	return runKillCommand(env, "kill", args, env.Config.Kill.AutoEscalate, false)
+}
+
+// runFree implements "tapas free": like kill, but escalates to SIGKILL and waits until the
+// ports are actually released. Meant for Makefiles that need a port before starting a server.
+func runFree(env Env, args []string) int {
+	return runKillCommand(env, "free", args, true, true)
+}
+
+func runKillCommand(env Env, name string, args []string, escalateDefault, waitDefault bool) int {
+	fs := newFlagSet(name, "[--signal SIG] [--timeout D] [--escalate] [--wait-until-free] [--only-if-project NAME] PORT...")
+	sigName := fs.String("signal", "TERM", "signal to send first (name or number)")
+	grace := time.Duration(env.Config.Kill.GracePeriod)
+	if grace <= 0 {
+		grace = ports.DefaultGracePeriod
+	}
+	timeout := fs.Duration("timeout", grace, "how long to wait for exit and port release (default from kill.grace_period)")
+	escalate := fs.Bool("escalate", escalateDefault, "send SIGKILL when a process outlives --timeout")
+	waitFree := fs.Bool("wait-until-free", waitDefault, "succeed only once nothing listens on the ports (a launcher may respawn the server)")
+	onlyProject := fs.String("only-if-project", "", "only signal listeners of this project (name or cwd base name); others count as denied")
+	quiet := fs.Bool("quiet", false, "print nothing; rely on the exit code")
+	if code, ok := parseFlags(fs, args); !ok {
+		return code
+	}
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return ExitUsage
+	}
+	portNums, err := parsePorts(fs.Args())
+	if err != nil {
+		errorf(name, "%v", err)
+		return ExitUsage
+	}
+	sig, err := ports.ParseSignal(*sigName)
+	if err != nil {
+		errorf(name, "%v", err)
+		return ExitUsage
+	}
+	if *waitFree && !ports.LookupSignal(sig).Terminates {
+		errorf(name, "--wait-until-free needs a terminating signal, not %s", ports.LookupSignal(sig).Name)
+		return ExitUsage
+	}
+	opts := killOptions{
+		sig:         sig,
+		terminate:   ports.TerminateOptions{Signal: sig, Grace: *timeout, Escalate: *escalate && sig != syscall.SIGKILL},
+		waitFree:    *waitFree,
+		onlyProject: *onlyProject,
+		quiet:       *quiet,
+	}
+
+	list, err := env.Lister.List()
+	if err != nil {
+		return errorf(name, "%v", err)
+	}
+	var store *ports.RelaunchStore
+	if env.StateDir != "" {
+		store = ports.NewRelaunchStore(env.StateDir)
+	}
+	worst := outcomeAlreadyFree
+	done := make(map[int]ports.TerminateResult) // one process may hold several of the ports
+	for _, port := range portNums {
+		outcome, msg := killPort(port, list, opts, store, done)
+		if !opts.quiet {
+			fmt.Fprintf(os.Stdout, "%d: %s\n", port, msg)
+		}
+		if outcome > worst {
+			worst = outcome
+		}
+	}
+	return worst.exitCode()
+}
+
+// parsePorts accepts "3000" or ":3000" and rejects anything that is not a TCP port number.
+func parsePorts(args []string) ([]uint16, error) {
+	var out []uint16
+	for _, arg := range args {
+		n, err := strconv.ParseUint(strings.TrimPrefix(arg, ":"), 10, 16)
+		if err != nil || n == 0 {
+			return nil, fmt.Errorf("invalid port %q", arg)
+		}
+		if !containsPort(out, uint16(n)) {
+			out = append(out, uint16(n))
+		}
+	}
+	return out, nil
+}
+
+// heldPorts returns every port pid listens on in list; nil for an unknown PID.
+func heldPorts(list []ports.Port, pid int) []uint16 {
+	if pid <= 0 {
+		return nil
+	}
+	var out []uint16
+	for _, p := range list {
+		if p.PID == pid && !containsPort(out, p.PortNum) {
+			out = append(out, p.PortNum)
+		}
+	}
+	return out
+}
+
+// listenersOn returns one row per process listening on port (IPv4 and IPv6 sockets share a PID).
+func listenersOn(list []ports.Port, port uint16) []ports.Port {
+	var out []ports.Port
+	seen := make(map[int]bool)
+	for _, p := range list {
+		if p.PortNum != port || (p.PID > 0 && seen[p.PID]) {
+			continue
+		}
+		seen[p.PID] = true
+		out = append(out, p)
+	}
+	return out
+}
+
+// killPort handles one requested port of list. Every listener is checked before any is signalled,
+// so a denied listener leaves the whole port alone rather than half of it.
+func killPort(port uint16, list []ports.Port, opts killOptions, store *ports.RelaunchStore, done map[int]ports.TerminateResult) (portOutcome, string) {
+	targets := listenersOn(list, port)
+	if len(targets) == 0 {
+		if listening, err := ports.PortListening(port); err != nil || !listening {
+			return outcomeAlreadyFree, "already free"
+		}
+		return outcomeStillBound, "bound, but the listening process is not visible (try sudo)"
+	}
+	for _, p := range targets {
+		if reason := killDenyReason(p, heldPorts(list, p.PID), opts.onlyProject); reason != "" {
+			return outcomeDenied, fmt.Sprintf("%s: %s", describeTarget(p), reason)
+		}
+	}
+
+	terminates := ports.LookupSignal(opts.sig).Terminates
+	outcome := outcomeFreed
+	var msgs []string
+	for _, p := range targets {
+		o, msg := signalTarget(p, opts, terminates, store, done)
+		if o > outcome {
+			outcome = o
+		}
+		msgs = append(msgs, describeTarget(p)+" "+msg)
+	}
+	if outcome == outcomeFreed && opts.waitFree && !ports.WaitPortsFree([]uint16{port}, opts.terminate.Grace) {
+		outcome = outcomeStillBound
+		msgs = append(msgs, "port still bound (respawned or inherited socket)")
+	}
+	return outcome, strings.Join(msgs, "; ")
+}
+
+// killDenyReason explains why p must not be signalled; "" means go ahead. The policy is checked
+// on every port held by p's process. Protected listeners need the port number typed, which only
+// works when stdin is a terminal.
+func killDenyReason(p ports.Port, held []uint16, onlyProject string) string {
+	if onlyProject != "" && !strings.EqualFold(onlyProject, p.ProjectDisplayName) && !strings.EqualFold(onlyProject, p.Project()) {
+		return fmt.Sprintf("belongs to %s, not %s", ports.OrDash(ports.ProjectLabel(&p)), onlyProject)
+	}
+	switch v := ports.CheckPolicyPorts(p, held); v.Decision {
+	case ports.Deny:
+		return "denied by policy: " + v.Reason
+	case ports.Confirm:
+		if !isTerminal(os.Stdin) {
+			return "protected: " + v.Reason + " (run interactively to confirm)"
+		}
+		if !confirmTyped(fmt.Sprintf("Port %d is protected (%s).", v.Port, v.Reason), strconv.Itoa(int(v.Port))) {
+			return "protected: not confirmed"
+		}
+	}
+	return ""
+}
+
+// signalTarget sends the signal to one listener: containers are stopped with docker, processes
+// are terminated (and recorded for relaunch) or just signalled for non-terminating signals.
+func signalTarget(p ports.Port, opts killOptions, terminates bool, store *ports.RelaunchStore, done map[int]ports.TerminateResult) (portOutcome, string) {
+	switch {
+	case p.DockerContainerName != "" && terminates:
+		r := ports.RunContainerAction(p, ports.ContainerStop)
+		if !r.OK {
+			return outcomeStillBound, r.Error
+		}
+		return outcomeFreed, "stopped"
+	case p.DockerContainerName != "":
+		return outcomeStillBound, "skipped: container (use docker kill --signal)"
+	case p.PID <= 0:
+		return outcomeStillBound, "PID unknown (try sudo)"
+	case !terminates:
+		r := ports.SendSignal(p, opts.sig)
+		if !r.OK {
+			return signalOutcome(r, false), r.Error
+		}
+		return outcomeFreed, "sent " + ports.LookupSignal(opts.sig).Name
+	}
+	if r, ok := done[p.PID]; ok {
+		return signalOutcome(r.KillResult, r.Exited), "already signalled for an earlier port"
+	}
+	rec, canRelaunch := ports.NewRelaunchRecord(p, ports.RelaunchKilled)
+	r := ports.Terminate(p, opts.terminate)
+	if store != nil && canRelaunch && r.OK {
+		_ = store.Add(rec)
+	}
+	done[p.PID] = r
+	return signalOutcome(r.KillResult, r.Exited), r.Summary()
+}
+
+// signalOutcome classifies a signal result: EPERM (another user's process) is a denial,
+// like a policy refusal, so scripts can retry with sudo instead of waiting for the port.
+func signalOutcome(r ports.KillResult, exited bool) portOutcome {
+	switch {
+	case r.OK && exited:
+		return outcomeFreed
+	case r.NeedsPrivilege:
+		return outcomeDenied
+	}
+	return outcomeStillBound
+}
+
+// describeTarget names a listener for result lines, e.g. "next-server (PID 4242)".
+func describeTarget(p ports.Port) string {
+	if p.DockerContainerName != "" {
+		return "container " + p.DockerContainerName
+	}
+	return fmt.Sprintf("%s (PID %s)", ports.ProcessLabel(&p), pidLabel(p.PID))
+}
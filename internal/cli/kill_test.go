@@ -0,0 +1,144 @@
+package cli
+
+import (
+	"net"
+	"os/exec"
+	"strconv"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/javiercepeda/tapas/internal/ports"
+)
+
+// fakeLister returns a fixed listing.
+type fakeLister []ports.Port
+
+func (f fakeLister) List() ([]ports.Port, error) { return f, nil }
+
+// freePort returns a port nothing listens on (one the kernel just handed out and released).
+func freePort(t *testing.T) uint16 {
+	t.Helper()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Skip("cannot listen:", err)
+	}
+	port := uint16(l.Addr().(*net.TCPAddr).Port)
+	l.Close()
+	return port
+}
+
+// startSleep starts a process to terminate; it is reaped when the test ends.
+func startSleep(t *testing.T) int {
+	t.Helper()
+	cmd := exec.Command("sleep", "30")
+	if err := cmd.Start(); err != nil {
+		t.Skip("sleep not available:", err)
+	}
+	t.Cleanup(func() {
+		_ = cmd.Process.Kill()
+		_ = cmd.Wait()
+	})
+	return cmd.Process.Pid
+}
+
+func TestPortOutcomeExitCode(t *testing.T) {
+	tests := []struct {
+		outcome portOutcome
+		want    int
+	}{
+		{outcomeFreed, ExitFreed},
+		{outcomeAlreadyFree, ExitAlreadyFree},
+		{outcomeDenied, ExitDenied},
+		{outcomeStillBound, ExitStillBound},
+	}
+	for _, tt := range tests {
+		if got := tt.outcome.exitCode(); got != tt.want {
+			t.Errorf("outcome %d: exit code %d, want %d", tt.outcome, got, tt.want)
+		}
+	}
+}
+
+func TestSignalOutcome(t *testing.T) {
+	tests := []struct {
+		name   string
+		r      ports.KillResult
+		exited bool
+		want   portOutcome
+	}{
+		{"exited", ports.KillResult{OK: true}, true, outcomeFreed},
+		{"outlived the grace period", ports.KillResult{OK: true}, false, outcomeStillBound},
+		{"EPERM", ports.KillResult{Error: "permission denied", NeedsPrivilege: true}, false, outcomeDenied},
+		{"other failure", ports.KillResult{Error: "no such process"}, false, outcomeStillBound},
+	}
+	for _, tt := range tests {
+		if got := signalOutcome(tt.r, tt.exited); got != tt.want {
+			t.Errorf("%s: outcome %d, want %d", tt.name, got, tt.want)
+		}
+	}
+}
+
+// noSuchPID is never signalled: every case using it must be refused before signalling.
+const noSuchPID = 1 << 30
+
+func TestKillPort(t *testing.T) {
+	ports.SetPolicy(ports.Policy{Deny: ports.PolicyRules{Ports: []string{"22"}}})
+	defer ports.SetPolicy(ports.Policy{})
+	port := freePort(t)
+	opts := killOptions{sig: syscall.SIGTERM, terminate: ports.TerminateOptions{Signal: syscall.SIGTERM, Grace: 2 * time.Second}}
+	tests := []struct {
+		name        string
+		list        []ports.Port
+		onlyProject string
+		want        portOutcome
+	}{
+		{"nothing listening", nil, "", outcomeAlreadyFree},
+		{"built-in deny", []ports.Port{{PID: noSuchPID, PortNum: port, Process: "sshd"}}, "", outcomeDenied},
+		{"other port of the process denied", []ports.Port{
+			{PID: noSuchPID, PortNum: port, Process: "node"},
+			{PID: noSuchPID, PortNum: 22, Process: "node"},
+		}, "", outcomeDenied},
+		{"other project", []ports.Port{{PID: noSuchPID, PortNum: port, Process: "node", ProjectDisplayName: "blog"}}, "shop", outcomeDenied},
+		{"PID not visible", []ports.Port{{PortNum: port, Process: "—"}}, "", outcomeStillBound},
+		{"terminated", []ports.Port{{PID: startSleep(t), PortNum: port, Process: "sleep"}}, "", outcomeFreed},
+	}
+	for _, tt := range tests {
+		opts.onlyProject = tt.onlyProject
+		got, msg := killPort(port, tt.list, opts, nil, make(map[int]ports.TerminateResult))
+		if got != tt.want {
+			t.Errorf("%s: outcome %d (%s), want %d", tt.name, got, msg, tt.want)
+		}
+	}
+}
+
+func TestRunKillMostSevereWins(t *testing.T) {
+	free, denied, unknown := freePort(t), freePort(t)+1, freePort(t)+2
+	list := fakeLister{
+		{PID: noSuchPID, PortNum: denied, Process: "sshd"},
+		{PortNum: unknown, Process: "—"},
+	}
+	tests := []struct {
+		ports []uint16
+		want  int
+	}{
+		{[]uint16{free}, ExitAlreadyFree},
+		{[]uint16{free, denied}, ExitDenied},
+		{[]uint16{unknown, denied, free}, ExitStillBound},
+	}
+	for _, tt := range tests {
+		args := []string{"--quiet"}
+		for _, p := range tt.ports {
+			args = append(args, strconv.Itoa(int(p)))
+		}
+		if got := runKill(Env{Lister: list}, args); got != tt.want {
+			t.Errorf("kill %v: exit %d, want %d", tt.ports, got, tt.want)
+		}
+	}
+
+	pid := startSleep(t)
+	list = append(list, ports.Port{PID: pid, PortNum: denied + 10, Process: "sleep"})
+	args := []string{"--quiet", strconv.Itoa(int(free)), strconv.Itoa(int(denied + 10))}
+	if got := runKill(Env{Lister: list}, args); got != ExitFreed {
+		t.Errorf("kill free and terminated ports: exit %d, want %d", got, ExitFreed)
+	}
+}
@@ -0,0 +1,164 @@
+package cli
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"text/template"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/javiercepeda/tapas/internal/ports"
+)
+
+// runList implements "tapas list": the TUI's listing, filter and sort, printed for scripts.
+func runList(env Env, args []string) int {
+	fs := newFlagSet("list", "[--filter QUERY] [--sort KEY] [-o json|csv|table] [--template TEXT]")
+	filter := fs.String("filter", "", "search query, as in the TUI (free text, is:public, is:stale, cap:..., ns:...)")
+	sortBy := fs.String("sort", "port", "sort by port, uptime or process")
+	stale := fs.Bool("stale", false, "only stale listeners (orphaned, deleted cwd, replaced binary)")
+	format := fs.String("o", "", "output format: json, csv or table (default: styled table on a terminal, plain table otherwise)")
+	tmpl := fs.String("template", "", "Go text/template executed per listener, e.g. '{{.Port}} {{.PID}}' (fields of the JSON record)")
+	if code, ok := parseFlags(fs, args); !ok {
+		return code
+	}
+	sortKey, err := ports.ParseSortKey(*sortBy)
+	if err != nil {
+		return errorf("list", "%v", err)
+	}
+	list, err := env.Lister.List()
+	if err != nil {
+		return errorf("list", "%v", err)
+	}
+	if env.StateDir != "" {
+		_, _ = ports.NewActivityStore(env.StateDir).Observe(list) // feeds tapas prune --idle; best effort
+	}
+	list = ports.FilterAndSort(list, strings.ToLower(*filter), sortKey, *stale)
+
+	if *tmpl != "" {
+		return writeTemplate(os.Stdout, *tmpl, list)
+	}
+	switch *format {
+	case "json":
+		err = ports.WriteJSON(os.Stdout, list)
+	case "csv":
+		err = writeCSV(os.Stdout, list)
+	case "table":
+		err = writeTable(os.Stdout, list, false)
+	case "":
+		err = writeTable(os.Stdout, list, isTerminal(os.Stdout))
+	default:
+		fmt.Fprintf(os.Stderr, "tapas list: unknown format %q (use json, csv or table)\n", *format)
+		return ExitUsage
+	}
+	if err != nil {
+		return errorf("list", "%v", err)
+	}
+	return ExitOK
+}
+
+// csvHeader is the CSV column order; keep it stable, scripts index columns by position.
+var csvHeader = []string{"port", "protocol", "pid", "process", "label", "app", "bind", "bind_address", "public",
+	"connections", "environment", "project", "cwd", "framework", "container", "image", "uptime_seconds", "stale"}
+
+func writeCSV(w io.Writer, list []ports.Port) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(csvHeader); err != nil {
+		return err
+	}
+	for _, p := range list {
+		r := ports.NewRecord(p)
+		stale := make([]string, len(r.Stale))
+		for i, s := range r.Stale {
+			stale[i] = string(s)
+		}
+		row := []string{
+			strconv.Itoa(int(r.Port)), r.Protocol, strconv.Itoa(r.PID), r.Process, r.Label, r.App, r.Bind, r.BindAddress,
+			strconv.FormatBool(r.Public), strconv.Itoa(r.Connections), r.Environment, r.Project, r.WorkingDir,
+			r.Framework, r.Container, r.Image, strconv.FormatInt(r.UptimeSeconds, 10), strings.Join(stale, ";"),
+		}
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+var (
+	listHeaderStyle = lipgloss.NewStyle().Bold(true)
+	listPublicStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#E57373")) // TUI warning color
+	listDimStyle    = lipgloss.NewStyle().Foreground(lipgloss.Color("#6C757D"))
+)
+
+// writeTable prints an aligned table with the TUI's columns. styled adds the TUI's colors
+// (terminals only); the plain form is safe to pipe and grep.
+func writeTable(w io.Writer, list []ports.Port, styled bool) error {
+	if len(list) == 0 {
+		if styled {
+			fmt.Fprintln(os.Stderr, "No listening ports.")
+		}
+		return nil
+	}
+	var buf strings.Builder
+	tw := tabwriter.NewWriter(&buf, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "PORT\tPROTO\tPID\tPROCESS\tAPP\tBIND\tCONN\tENV\tPROJECT\tUPTIME")
+	for _, p := range list {
+		p := p
+		r := ports.NewRecord(p)
+		fmt.Fprintf(tw, "%d\t%s\t%s\t%s\t%s\t%s\t%d\t%s\t%s\t%s\n", r.Port, strings.ToUpper(r.Protocol), pidLabel(r.PID),
+			r.Label, r.App, r.Bind, r.Connections, ports.OrDash(r.Environment), ports.OrDash(ports.ProjectLabel(&p)), r.Uptime)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	if !styled {
+		_, err := io.WriteString(w, buf.String())
+		return err
+	}
+	// Colorize after alignment so ANSI codes do not count toward column widths.
+	lines := strings.Split(strings.TrimRight(buf.String(), "\n"), "\n")
+	for i, line := range lines {
+		switch {
+		case i == 0:
+			line = listHeaderStyle.Render(line)
+		case list[i-1].Stopped || ports.IsSystemPort(list[i-1].PortNum):
+			line = listDimStyle.Render(line)
+		case ports.IsPublicBind(list[i-1].BindAddress):
+			line = strings.Replace(line, " PUBLIC ", " "+listPublicStyle.Render("PUBLIC")+" ", 1)
+		}
+		if _, err := fmt.Fprintln(w, line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func pidLabel(pid int) string {
+	if pid <= 0 {
+		return "-"
+	}
+	return strconv.Itoa(pid)
+}
+
+// writeTemplate executes text once per listener with its Record; a trailing newline is added
+// when the template has none, like docker --format.
+func writeTemplate(w io.Writer, text string, list []ports.Port) int {
+	if !strings.HasSuffix(text, "\n") {
+		text += "\n"
+	}
+	t, err := template.New("list").Funcs(template.FuncMap{"join": strings.Join}).Parse(text)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "tapas list: template: %v\n", err)
+		return ExitUsage
+	}
+	for _, p := range list {
+		if err := t.Execute(w, ports.NewRecord(p)); err != nil {
+			return errorf("list", "template: %v", err)
+		}
+	}
+	return ExitOK
+}
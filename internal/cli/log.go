@@ -0,0 +1,105 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/javiercepeda/tapas/internal/ports"
+)
+
+// runLog implements "tapas log": print audit entries, oldest first, optionally filtered.
+func runLog(env Env, args []string) int {
+	fs := newFlagSet("log", "[flags]")
+	port := fs.Uint("port", 0, "only entries for this port")
+	pid := fs.Int("pid", 0, "only entries for this PID")
+	usr := fs.String("user", "", "only entries by this user (substring)")
+	process := fs.String("process", "", "only entries whose process or command contains this")
+	action := fs.String("action", "", "only this action: signal, container, relaunch")
+	since := fs.String("since", "", "only entries newer than a duration (24h) or date (2006-01-02)")
+	failed := fs.Bool("failed", false, "only failed attempts")
+	last := fs.Int("n", 0, "show only the last N matching entries")
+	asJSON := fs.Bool("json", false, "print matching entries as JSON lines")
+	file := fs.String("file", "", "read this audit log instead of the configured one")
+	if code, ok := parseFlags(fs, args); !ok {
+		return code
+	}
+	if *port > 65535 {
+		return errorf("log", "invalid port %d", *port)
+	}
+	filter := ports.AuditFilter{Port: uint16(*port), PID: *pid, User: *usr, Process: *process, Action: *action, FailedOnly: *failed}
+	if *since != "" {
+		t, err := parseSince(*since)
+		if err != nil {
+			return errorf("log", "--since: %v", err)
+		}
+		filter.Since = t
+	}
+	path := *file
+	if path == "" {
+		path = ports.AuditLogPath()
+	}
+	if path == "" {
+		return errorf("log", "audit log is disabled (audit.disabled in config)")
+	}
+	entries, err := ports.ReadAuditLog(path)
+	if err != nil {
+		return errorf("log", "%v", err)
+	}
+	var matched []ports.AuditEntry
+	for _, e := range entries {
+		if filter.Match(e) {
+			matched = append(matched, e)
+		}
+	}
+	if *last > 0 && len(matched) > *last {
+		matched = matched[len(matched)-*last:]
+	}
+	if *asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		for _, e := range matched {
+			if err := enc.Encode(e); err != nil {
+				return errorf("log", "%v", err)
+			}
+		}
+		return ExitOK
+	}
+	if len(matched) == 0 {
+		fmt.Fprintln(os.Stderr, "No matching entries in", path)
+		return ExitOK
+	}
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "TIME\tUSER\tACTION\tSIGNAL\tPORT\tPID\tPROCESS\tRESULT")
+	for _, e := range matched {
+		port := "-"
+		if e.Port > 0 {
+			port = fmt.Sprint(e.Port)
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%d\t%s\t%s\n", e.Time.Local().Format("2006-01-02 15:04:05"),
+			e.User, e.Action, ports.OrDash(e.Signal), port, e.PID, ports.OrDash(e.Process), e.Result)
+	}
+	return flushOrFail(tw, "log")
+}
+
+// parseSince accepts a duration back from now ("24h", "90m") or a local date/time.
+func parseSince(s string) (time.Time, error) {
+	if d, err := time.ParseDuration(s); err == nil {
+		return time.Now().Add(-d), nil
+	}
+	for _, layout := range []string{"2006-01-02", "2006-01-02 15:04", time.RFC3339} {
+		if t, err := time.ParseInLocation(layout, s, time.Local); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid time %q (use a duration like 24h or a date like 2006-01-02)", s)
+}
+
+// flushOrFail flushes tw and reports write errors (e.g. a closed pipe).
+func flushOrFail(tw *tabwriter.Writer, cmd string) int {
+	if err := tw.Flush(); err != nil {
+		return errorf(cmd, "%v", err)
+	}
+	return ExitOK
+}
@@ -0,0 +1,142 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/javiercepeda/tapas/internal/ports"
+	"github.com/javiercepeda/tapas/internal/ui"
+	"github.com/muesli/termenv"
+)
+
+// ExitCancelled is returned when the picker is closed without choosing (as fzf does).
+const ExitCancelled = 130
+
+// pickFields are the values tapas pick can print, by name.
+var pickFields = map[string]func(p ports.Port) string{
+	"port":      func(p ports.Port) string { return strconv.Itoa(int(p.PortNum)) },
+	"pid":       pickPID,
+	"url":       pickURL,
+	"cwd":       func(p ports.Port) string { return p.WorkingDir },
+	"container": func(p ports.Port) string { return p.DockerContainerName },
+	"process":   func(p ports.Port) string { return p.Process },
+	"project":   func(p ports.Port) string { return ports.NewRecord(p).Project },
+	"command":   func(p ports.Port) string { return p.SafeCommand() },
+}
+
+// runPick implements "tapas pick": the TUI as a picker. The UI draws on the terminal through
+// stderr, so stdout carries only the chosen fields: curl localhost:$(tapas pick).
+func runPick(env Env, args []string) int {
+	fs := newFlagSet("pick", "[--field port,pid,url,cwd,container,...] [--format lines|json] [--filter QUERY] [--sort KEY]")
+	fieldList := fs.String("field", "port", "comma-separated fields to print: port, pid, url, cwd, container, process, project, command")
+	format := fs.String("format", "lines", "lines: one row per line, fields tab-separated; json: an array of objects")
+	filter := fs.String("filter", "", "start with this search query")
+	sortBy := fs.String("sort", "port", "start sorted by port, uptime or process")
+	stale := fs.Bool("stale", false, "start with only stale listeners")
+	ascii := fs.Bool("ascii", false, "ASCII indicators only")
+	if code, ok := parseFlags(fs, args); !ok {
+		return code
+	}
+	var fields []string
+	for _, f := range strings.Split(*fieldList, ",") {
+		f = strings.TrimSpace(strings.ToLower(f))
+		if _, ok := pickFields[f]; !ok {
+			errorf("pick", "unknown field %q", f)
+			return ExitUsage
+		}
+		fields = append(fields, f)
+	}
+	if *format != "lines" && *format != "json" {
+		errorf("pick", "unknown format %q (use lines or json)", *format)
+		return ExitUsage
+	}
+	sortKey, err := ports.ParseSortKey(*sortBy)
+	if err != nil {
+		errorf("pick", "%v", err)
+		return ExitUsage
+	}
+
+	tty, err := pickTerminal()
+	if err != nil {
+		return errorf("pick", "needs a terminal: %v", err)
+	}
+	if tty != os.Stderr {
+		defer tty.Close()
+	}
+	// Styles render for the terminal we draw on, not for stdout (usually a pipe).
+	lipgloss.DefaultRenderer().SetOutput(termenv.NewOutput(tty))
+
+	m := ui.NewModel(env.Lister, *ascii)
+	m.PickMode = true
+	m.SetFilter(*filter, sortKey, *stale)
+	final, err := tea.NewProgram(m, tea.WithAltScreen(), tea.WithOutput(tty), tea.WithInputTTY()).Run()
+	if err != nil {
+		return errorf("pick", "%v", err)
+	}
+	picked := final.(ui.Model).Picked()
+	if len(picked) == 0 {
+		return ExitCancelled
+	}
+	if err := writePicked(os.Stdout, picked, fields, *format); err != nil {
+		return errorf("pick", "%v", err)
+	}
+	return ExitOK
+}
+
+// pickTerminal returns where the picker draws: stderr when it is a terminal, else /dev/tty.
+func pickTerminal() (*os.File, error) {
+	if isTerminal(os.Stderr) {
+		return os.Stderr, nil
+	}
+	return os.OpenFile("/dev/tty", os.O_WRONLY, 0)
+}
+
+func writePicked(w io.Writer, picked []ports.Port, fields []string, format string) error {
+	if format == "json" {
+		rows := make([]map[string]string, len(picked))
+		for i, p := range picked {
+			rows[i] = make(map[string]string, len(fields))
+			for _, f := range fields {
+				rows[i][f] = pickFields[f](p)
+			}
+		}
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(rows)
+	}
+	for _, p := range picked {
+		values := make([]string, len(fields))
+		for i, f := range fields {
+			values[i] = pickFields[f](p)
+		}
+		if _, err := fmt.Fprintln(w, strings.Join(values, "\t")); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// pickPID is empty when the PID is not visible (another user's process without root), so
+// kill $(tapas pick --field pid) never becomes kill 0.
+func pickPID(p ports.Port) string {
+	if p.PID <= 0 {
+		return ""
+	}
+	return strconv.Itoa(p.PID)
+}
+
+// pickURL is an http URL for the listener: localhost for loopback and wildcard binds.
+func pickURL(p ports.Port) string {
+	host := "localhost"
+	if addr := strings.Trim(p.BindAddress, "[]"); addr != "" && ports.BindLabel(p.BindAddress) != "LOCAL" && !ports.IsPublicBind(p.BindAddress) {
+		host = addr
+	}
+	return "http://" + net.JoinHostPort(host, strconv.Itoa(int(p.PortNum)))
+}
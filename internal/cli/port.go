@@ -0,0 +1,113 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/javiercepeda/tapas/internal/ports"
+)
+
+// runPort implements "tapas port <subcommand>". Only suggest exists today.
+func runPort(env Env, args []string) int {
+	if len(args) > 0 && args[0] == "suggest" {
+		return runPortSuggest(env, args[1:])
+	}
+	fmt.Fprintln(os.Stderr, "Usage: tapas port suggest [flags]")
+	fmt.Fprintln(os.Stderr, "\n  suggest    Print a free port for a project, stable across runs")
+	if len(args) > 0 && (args[0] == "-h" || args[0] == "--help") {
+		return ExitOK
+	}
+	return ExitUsage
+}
+
+// runPortSuggest implements "tapas port suggest".
+func runPortSuggest(env Env, args []string) int {
+	fs := newFlagSet("port suggest", "[--project NAME] [--range FROM-TO] [--reserve D] [--env VAR] [-v]")
+	project := fs.String("project", "", "project name the port is derived from (default: the current directory's project)")
+	rangeFlag := fs.String("range", env.Config.Suggest.Range, "ports to choose from (default from suggest.range, else 3000-9999)")
+	reserve := fs.Duration("reserve", 0, "hold the port for this project for a while (e.g. 10m) so other projects are not suggested it")
+	envVar := fs.String("env", "", "print VAR=PORT instead of the bare port, for .env files")
+	verbose := fs.Bool("v", false, "explain on stderr why earlier candidates were skipped")
+	if code, ok := parseFlags(fs, args); !ok {
+		return code
+	}
+	if fs.NArg() > 0 {
+		fs.Usage()
+		return ExitUsage
+	}
+	opts := ports.SuggestOptions{Project: *project}
+	if *rangeFlag != "" {
+		r, err := ports.ParsePortRange(*rangeFlag)
+		if err != nil {
+			errorf("port suggest", "%v", err)
+			return ExitUsage
+		}
+		opts.Range = r
+	}
+	for _, spec := range env.Config.Suggest.Reserved {
+		r, err := ports.ParsePortRange(spec)
+		if err != nil {
+			return errorf("port suggest", "suggest.reserved: %v", err)
+		}
+		opts.Avoid = append(opts.Avoid, r)
+	}
+	// The project's own .env may already hold the port an earlier run printed; it stays a candidate.
+	cwd, err := os.Getwd()
+	if err == nil {
+		opts.Own = cwd
+	}
+	if opts.Project == "" {
+		opts.Project = ports.ProjectDisplayName(cwd)
+		if opts.Project == "" {
+			opts.Project = filepath.Base(cwd)
+		}
+	}
+	opts.Repos = append(opts.Repos, expandRepos(env.Config.Suggest.Repos)...)
+
+	if opts.Listening, err = env.Lister.List(); err != nil {
+		return errorf("port suggest", "%v", err)
+	}
+	var s ports.Suggestion
+	switch {
+	case env.StateDir != "":
+		s, err = ports.NewReservationStore(env.StateDir).Suggest(opts, *reserve)
+	case *reserve > 0:
+		return errorf("port suggest", "--reserve needs the state directory, which is unknown")
+	default:
+		s, err = ports.SuggestPort(opts)
+	}
+	if *verbose {
+		fmt.Fprintf(os.Stderr, "project %q prefers %d\n", opts.Project, s.Preferred)
+		for _, skipped := range s.Skipped {
+			fmt.Fprintf(os.Stderr, "  skipped %s\n", skipped)
+		}
+	}
+	if err != nil {
+		return errorf("port suggest", "%v", err)
+	}
+	if *envVar != "" {
+		fmt.Printf("%s=%d\n", *envVar, s.Port)
+	} else {
+		fmt.Println(s.Port)
+	}
+	return ExitOK
+}
+
+// expandRepos resolves suggest.repos globs, expanding a leading ~ to the home directory.
+func expandRepos(patterns []string) []string {
+	home, _ := os.UserHomeDir()
+	var out []string
+	for _, pattern := range patterns {
+		if home != "" && (pattern == "~" || strings.HasPrefix(pattern, "~/")) {
+			pattern = filepath.Join(home, pattern[1:])
+		}
+		matches, err := filepath.Glob(pattern)
+		if err != nil {
+			continue
+		}
+		out = append(out, matches...)
+	}
+	return out
+}
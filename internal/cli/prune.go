@@ -0,0 +1,234 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/javiercepeda/tapas/internal/ports"
+)
+
+// PruneSchema identifies the tapas prune --json summary.
+const PruneSchema = "tapas/prune/v1"
+
+// pruneSummary is the --json output: what was (or would be) terminated and what was skipped.
+type pruneSummary struct {
+	Schema     string      `json:"schema"`
+	DryRun     bool        `json:"dry_run"`
+	Terminated []pruneItem `json:"terminated"`
+	Skipped    []pruneItem `json:"skipped"`
+}
+
+// pruneItem is one process; a process listening on several ports is pruned once.
+type pruneItem struct {
+	Ports   []uint16 `json:"ports"`
+	PID     int      `json:"pid"`
+	Process string   `json:"process"`
+	Project string   `json:"project,omitempty"`
+	OK      bool     `json:"ok"`
+	Result  string   `json:"result"`
+
+	target ports.Port
+}
+
+// runPrune implements "tapas prune": terminate listeners matching every given criterion.
+func runPrune(env Env, args []string) int {
+	fs := newFlagSet("prune", "[criteria] [--dry-run | --yes] [--json]")
+	idle := fs.Duration("idle", 0, "no connections observed for at least this long (e.g. 4h; needs activity history)")
+	olderThan := fs.Duration("older-than", 0, "running for at least this long (e.g. 24h)")
+	orphaned := fs.Bool("orphaned", false, "only listeners reparented to init (their terminal or launcher is gone)")
+	project := fs.String("project", "", "only listeners of this project")
+	devOnly := fs.Bool("dev-only", true, "only dev servers (framework or launcher detected); --dev-only=false for any listener")
+	dryRun := fs.Bool("dry-run", false, "show what would be terminated, do nothing")
+	yes := fs.Bool("yes", false, "do not ask for confirmation")
+	asJSON := fs.Bool("json", false, "print a JSON summary")
+	if code, ok := parseFlags(fs, args); !ok {
+		return code
+	}
+	criteria := ports.PruneCriteria{Idle: *idle, OlderThan: *olderThan, Orphaned: *orphaned, Project: *project, DevOnly: *devOnly}
+
+	list, err := env.Lister.List()
+	if err != nil {
+		return errorf("prune", "%v", err)
+	}
+	var activity ports.ActivityIndex
+	if env.StateDir != "" {
+		activity, err = ports.NewActivityStore(env.StateDir).Observe(list)
+		if err != nil && *idle > 0 {
+			return errorf("prune", "activity history: %v", err)
+		}
+	}
+	if *idle > 0 && activity == nil {
+		return errorf("prune", "--idle needs activity history, but the state directory is unknown")
+	}
+
+	summary := pruneSummary{Schema: PruneSchema, DryRun: *dryRun, Terminated: []pruneItem{}, Skipped: []pruneItem{}}
+	now := time.Now()
+	for _, item := range groupByProcess(list) {
+		p := item.target
+		var act *ports.Activity
+		if a, ok := activity.Lookup(p); ok {
+			act = &a
+		}
+		if !criteria.Match(p, act, now) {
+			continue
+		}
+		if reason := pruneSkipReason(item); reason != "" {
+			item.Result = reason
+			summary.Skipped = append(summary.Skipped, item)
+			continue
+		}
+		item.OK, item.Result = true, "would terminate"
+		summary.Terminated = append(summary.Terminated, item)
+	}
+
+	if !*dryRun && len(summary.Terminated) > 0 {
+		if !*yes {
+			if !isTerminal(os.Stdin) {
+				errorf("prune", "refusing to terminate without --yes when stdin is not a terminal")
+				return ExitUsage
+			}
+			printPruneTable(summary, false)
+			if !confirm(fmt.Sprintf("Terminate %d process(es)?", len(summary.Terminated))) {
+				fmt.Fprintln(os.Stderr, "Nothing terminated.")
+				return ExitOK
+			}
+		}
+		pruneAll(env, summary.Terminated)
+	}
+
+	if *asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(summary); err != nil {
+			return errorf("prune", "%v", err)
+		}
+	} else {
+		printPruneTable(summary, true)
+	}
+	for _, item := range summary.Terminated {
+		if !*dryRun && !item.OK {
+			return ExitError
+		}
+	}
+	return ExitOK
+}
+
+// groupByProcess collapses rows to one item per PID (IPv4/IPv6 and multi-port servers), by port.
+func groupByProcess(list []ports.Port) []pruneItem {
+	byPID := make(map[int]*pruneItem)
+	var out []*pruneItem
+	for _, p := range list {
+		if item, ok := byPID[p.PID]; ok && p.PID > 0 {
+			if !containsPort(item.Ports, p.PortNum) {
+				item.Ports = append(item.Ports, p.PortNum)
+			}
+			continue
+		}
+		item := &pruneItem{Ports: []uint16{p.PortNum}, PID: p.PID, Process: p.Process, Project: p.ProjectDisplayName, target: p}
+		if item.Project == "" && p.WorkingDir != "" {
+			item.Project = p.Project()
+		}
+		byPID[p.PID] = item
+		out = append(out, item)
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Ports[0] < out[j].Ports[0] })
+	items := make([]pruneItem, len(out))
+	for i, item := range out {
+		items[i] = *item
+	}
+	return items
+}
+
+func containsPort(list []uint16, port uint16) bool {
+	for _, p := range list {
+		if p == port {
+			return true
+		}
+	}
+	return false
+}
+
+// pruneSkipReason explains why a matching process is left alone; "" means it can be pruned.
+// Policy is checked on every port it holds. Protected listeners need typed confirmation,
+// which a batch command cannot collect.
+func pruneSkipReason(item pruneItem) string {
+	p := item.target
+	switch v := ports.CheckPolicyPorts(p, item.Ports); {
+	case v.Decision == ports.Deny:
+		return "denied by policy: " + v.Reason
+	case v.Decision == ports.Confirm:
+		return "protected: " + v.Reason
+	case p.DockerContainerName != "" || p.InDocker:
+		return "container (use docker stop)"
+	case p.PID <= 0:
+		return "PID unknown"
+	}
+	return ""
+}
+
+// pruneAll terminates items concurrently (each may wait out its grace period), keeping
+// relaunch records like the UI does.
+func pruneAll(env Env, items []pruneItem) {
+	opts := ports.TerminateOptions{Grace: time.Duration(env.Config.Kill.GracePeriod), Escalate: env.Config.Kill.AutoEscalate}
+	var store *ports.RelaunchStore
+	if env.StateDir != "" {
+		store = ports.NewRelaunchStore(env.StateDir)
+	}
+	var wg sync.WaitGroup
+	for i := range items {
+		wg.Add(1)
+		go func(item *pruneItem) {
+			defer wg.Done()
+			rec, canRelaunch := ports.NewRelaunchRecord(item.target, ports.RelaunchKilled)
+			r := ports.Terminate(item.target, opts)
+			if store != nil && canRelaunch && r.OK {
+				_ = store.Add(rec)
+			}
+			item.OK = r.OK && r.Exited
+			item.Result = r.Summary()
+		}(&items[i])
+	}
+	wg.Wait()
+}
+
+// printPruneTable prints candidates (before confirmation) or results (final) to stderr/stdout.
+func printPruneTable(s pruneSummary, final bool) {
+	out := os.Stderr
+	if final {
+		out = os.Stdout
+	}
+	if final && len(s.Terminated) == 0 && len(s.Skipped) == 0 {
+		fmt.Fprintln(out, "Nothing to prune.")
+		return
+	}
+	tw := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "PORTS\tPID\tPROCESS\tPROJECT\tRESULT")
+	for _, item := range s.Terminated {
+		result := item.Result
+		if !final {
+			result = "will terminate"
+		}
+		fmt.Fprintf(tw, "%s\t%d\t%s\t%s\t%s\n", joinPorts(item.Ports), item.PID, item.Process, ports.OrDash(item.Project), result)
+	}
+	if final {
+		for _, item := range s.Skipped {
+			fmt.Fprintf(tw, "%s\t%d\t%s\t%s\tskipped: %s\n", joinPorts(item.Ports), item.PID, item.Process, ports.OrDash(item.Project), item.Result)
+		}
+	}
+	tw.Flush()
+}
+
+// joinPorts formats ports as "3000,3001".
+func joinPorts(list []uint16) string {
+	parts := make([]string, len(list))
+	for i, p := range list {
+		parts[i] = fmt.Sprint(p)
+	}
+	return strings.Join(parts, ",")
+}
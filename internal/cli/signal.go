@@ -0,0 +1,57 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/javiercepeda/tapas/internal/ports"
+)
+
+// runSignal implements "tapas signal": the single-purpose helper the UI runs through sudo when a
+// kill is denied. It re-verifies the target (start time, executable) before signalling. As root it
+// loads root's config, so the invoking user's deny rules come in --deny and are checked on top of
+// root's policy; the signal is audited in root's log, and the UI records it in the user's.
+func runSignal(env Env, args []string) int {
+	fs := newFlagSet("signal", "--pid N --sig SIG --expect-start UNIX [--expect-exe PATH] [--port N] [--deny RULES]")
+	pid := fs.Int("pid", 0, "process to signal")
+	sigName := fs.String("sig", "TERM", "signal name or number")
+	expectStart := fs.Float64("expect-start", 0, "process start time (Unix seconds, fractional) the PID must still have")
+	expectExe := fs.String("expect-exe", "", "executable path the PID must still run")
+	port := fs.Uint("port", 0, "port the process listens on, for port rules and the audit log")
+	denyJSON := fs.String("deny", "", "the invoking user's deny rules (JSON), checked in addition to this config's policy")
+	if code, ok := parseFlags(fs, args); !ok {
+		return code
+	}
+	if *pid <= 0 || *expectStart <= 0 || *port > 65535 {
+		fs.Usage()
+		return ExitUsage
+	}
+	sig, err := ports.ParseSignal(*sigName)
+	if err != nil {
+		return errorf("signal", "%v", err)
+	}
+	p, err := ports.VerifiedPort(*pid, time.Unix(0, int64(*expectStart*1e9)), *expectExe)
+	if err != nil {
+		return errorf("signal", "PID %d: %v", *pid, err)
+	}
+	p.PortNum = uint16(*port)
+	if *denyJSON != "" {
+		user := ports.Policy{}
+		if err := json.Unmarshal([]byte(*denyJSON), &user.Deny); err != nil {
+			return errorf("signal", "--deny: %v", err)
+		}
+		if err := ports.ValidatePolicy(user); err != nil {
+			return errorf("signal", "--deny: %v", err)
+		}
+		if v := user.Check(p); v.Decision == ports.Deny {
+			return errorf("signal", "PID %d: denied by the invoking user's policy: %s", *pid, v.Reason)
+		}
+	}
+	if r := ports.Signal(p, sig); !r.OK {
+		return errorf("signal", "PID %d: %s", *pid, r.Error)
+	}
+	fmt.Fprintf(os.Stderr, "Sent %s to PID %d (%s).\n", ports.LookupSignal(sig).Name, p.PID, p.Process)
+	return ExitOK
+}
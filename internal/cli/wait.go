@@ -0,0 +1,158 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/javiercepeda/tapas/internal/ports"
+)
+
+// Exit codes of tapas wait besides ExitOK (the port reached the requested state).
+const (
+	ExitTimeout = 3 // the port did not reach the requested state before --timeout
+	ExitTaken   = 4 // the port is held by a listener that does not match the requirements
+)
+
+// httpProbeTimeout bounds one readiness request; a hanging server must not eat the whole --timeout.
+const httpProbeTimeout = 2 * time.Second
+
+// waitState is the result of one poll.
+type waitState struct {
+	done  bool   // the requested state is reached
+	taken bool   // something else holds the port
+	text  string // progress line
+}
+
+// runWait implements "tapas wait": poll the lister until a port is listening (optionally by a
+// given process, container or bind address, and answering an HTTP probe) or free.
+func runWait(env Env, args []string) int {
+	fs := newFlagSet("wait", "--port N [--listening | --free] [--timeout D] [--process GLOB] [--container GLOB] [--bind ADDR] [--http PATH|URL]")
+	portNum := fs.Uint("port", 0, "port to wait for")
+	listening := fs.Bool("listening", false, "wait until the port is listening (default)")
+	free := fs.Bool("free", false, "wait until nothing (or nothing matching --process/--container/--bind) listens on the port")
+	timeout := fs.Duration("timeout", 30*time.Second, "give up after this long")
+	interval := fs.Duration("interval", 500*time.Millisecond, "time between polls")
+	var spec ports.ListenerSpec
+	fs.StringVar(&spec.Process, "process", "", "require the listener's process name (glob, e.g. 'postgres*')")
+	fs.StringVar(&spec.Container, "container", "", "require the Docker container name (glob)")
+	fs.StringVar(&spec.Bind, "bind", "", "require the bind address (127.0.0.1, ::, ...) or LOCAL / PUBLIC")
+	probe := fs.String("http", "", "also require an HTTP 2xx from this path on the port (e.g. /health) or full URL")
+	quiet := fs.Bool("quiet", false, "no progress on stderr; rely on the exit code")
+	if code, ok := parseFlags(fs, args); !ok {
+		return code
+	}
+	if *portNum == 0 || *portNum > 65535 || fs.NArg() > 0 || (*listening && *free) || *interval <= 0 {
+		fs.Usage()
+		return ExitUsage
+	}
+	if *free && *probe != "" {
+		errorf("wait", "--http only applies to --listening")
+		return ExitUsage
+	}
+	port := uint16(*portNum)
+
+	start := time.Now()
+	deadline := start.Add(*timeout)
+	last := ""
+	for {
+		var st waitState
+		list, err := env.Lister.List()
+		switch {
+		case err != nil:
+			st.text = "listing failed: " + err.Error()
+		case *free:
+			st = checkFree(listenersOn(list, port), spec)
+		default:
+			st = checkListening(listenersOn(list, port), spec, *probe)
+		}
+		if !*quiet && st.text != last {
+			fmt.Fprintf(os.Stderr, "[%5.1fs] port %d: %s\n", time.Since(start).Seconds(), port, st.text)
+			last = st.text
+		}
+		if st.done {
+			return ExitOK
+		}
+		if time.Now().After(deadline) {
+			if !*quiet {
+				fmt.Fprintf(os.Stderr, "tapas wait: gave up after %s\n", *timeout)
+			}
+			if st.taken {
+				return ExitTaken
+			}
+			return ExitTimeout
+		}
+		time.Sleep(*interval)
+	}
+}
+
+// checkListening is ready when a matching listener holds the port and, with a probe, answers 2xx.
+func checkListening(rows []ports.Port, spec ports.ListenerSpec, probe string) waitState {
+	if len(rows) == 0 {
+		return waitState{text: "not listening yet"}
+	}
+	for _, p := range rows {
+		if !spec.Match(p) {
+			continue
+		}
+		text := "listening: " + describeTarget(p)
+		if probe == "" {
+			return waitState{done: true, text: text}
+		}
+		url := probeURL(probe, p)
+		status, err := httpStatus(url)
+		if err != nil {
+			return waitState{text: fmt.Sprintf("%s; GET %s: %v", text, url, err)}
+		}
+		return waitState{done: status >= 200 && status < 300, text: fmt.Sprintf("%s; GET %s: %d", text, url, status)}
+	}
+	return waitState{taken: true, text: "held by " + describeTarget(rows[0]) + ", not the expected listener"}
+}
+
+// checkFree is done when no matching listener is left and nobody else took the port.
+func checkFree(rows []ports.Port, spec ports.ListenerSpec) waitState {
+	if len(rows) == 0 {
+		return waitState{done: true, text: "free"}
+	}
+	for _, p := range rows {
+		if spec.Match(p) {
+			return waitState{text: "still held by " + describeTarget(p)}
+		}
+	}
+	return waitState{taken: true, text: "released, but now held by " + describeTarget(rows[0])}
+}
+
+// probeURL turns a path into a URL on the listener's address; full URLs are used as given.
+func probeURL(probe string, p ports.Port) string {
+	if strings.Contains(probe, "://") {
+		return probe
+	}
+	host := "127.0.0.1"
+	if !ports.IsPublicBind(p.BindAddress) {
+		host = strings.Trim(p.BindAddress, "[]")
+	}
+	if !strings.HasPrefix(probe, "/") {
+		probe = "/" + probe
+	}
+	return "http://" + net.JoinHostPort(host, strconv.Itoa(int(p.PortNum))) + probe
+}
+
+func httpStatus(target string) (int, error) {
+	client := &http.Client{Timeout: httpProbeTimeout}
+	resp, err := client.Get(target)
+	if err != nil {
+		var uerr *url.Error
+		if errors.As(err, &uerr) {
+			err = uerr.Err // drop the repeated method and URL
+		}
+		return 0, err
+	}
+	resp.Body.Close()
+	return resp.StatusCode, nil
+}
@@ -0,0 +1,79 @@
+package cli
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/javiercepeda/tapas/internal/ports"
+)
+
+func TestCheckListening(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/health" {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer srv.Close()
+	addr := srv.Listener.Addr().(*net.TCPAddr)
+	pg := ports.Port{PID: 10, PortNum: uint16(addr.Port), Process: "postgres", BindAddress: "127.0.0.1"}
+	node := ports.Port{PID: 20, PortNum: uint16(addr.Port), Process: "node", BindAddress: "0.0.0.0"}
+
+	tests := []struct {
+		name        string
+		rows        []ports.Port
+		spec        ports.ListenerSpec
+		probe       string
+		done, taken bool
+	}{
+		{"not listening", nil, ports.ListenerSpec{}, "", false, false},
+		{"any listener", []ports.Port{node}, ports.ListenerSpec{}, "", true, false},
+		{"matching process", []ports.Port{node, pg}, ports.ListenerSpec{Process: "postgres*"}, "", true, false},
+		{"non-matching listener", []ports.Port{node}, ports.ListenerSpec{Process: "postgres*"}, "", false, true},
+		{"non-matching bind", []ports.Port{node}, ports.ListenerSpec{Bind: "LOCAL"}, "", false, true},
+		{"probe ok", []ports.Port{pg}, ports.ListenerSpec{}, "/health", true, false},
+		{"probe not ready", []ports.Port{pg}, ports.ListenerSpec{}, "/", false, false},
+	}
+	for _, tt := range tests {
+		st := checkListening(tt.rows, tt.spec, tt.probe)
+		if st.done != tt.done || st.taken != tt.taken {
+			t.Errorf("%s: done=%v taken=%v (%s), want done=%v taken=%v", tt.name, st.done, st.taken, st.text, tt.done, tt.taken)
+		}
+	}
+}
+
+func TestCheckFree(t *testing.T) {
+	pg := ports.Port{PID: 10, PortNum: 5432, Process: "postgres"}
+	node := ports.Port{PID: 20, PortNum: 5432, Process: "node"}
+	spec := ports.ListenerSpec{Process: "postgres"}
+	tests := []struct {
+		name        string
+		rows        []ports.Port
+		spec        ports.ListenerSpec
+		done, taken bool
+	}{
+		{"free", nil, spec, true, false},
+		{"still held", []ports.Port{pg}, spec, false, false},
+		{"still held by anything", []ports.Port{node}, ports.ListenerSpec{}, false, false},
+		{"free, then taken by another", []ports.Port{node}, spec, false, true},
+	}
+	for _, tt := range tests {
+		st := checkFree(tt.rows, tt.spec)
+		if st.done != tt.done || st.taken != tt.taken {
+			t.Errorf("%s: done=%v taken=%v (%s), want done=%v taken=%v", tt.name, st.done, st.taken, st.text, tt.done, tt.taken)
+		}
+	}
+}
+
+func TestRunWaitFreeThenTaken(t *testing.T) {
+	env := Env{Lister: fakeLister{{PID: 20, PortNum: 5432, Process: "node"}}}
+	args := []string{"--port", "5432", "--free", "--process", "postgres", "--timeout", "0", "--interval", "1ms", "--quiet"}
+	if got := runWait(env, args); got != ExitTaken {
+		t.Errorf("wait --free with another process on the port: exit %d, want %d", got, ExitTaken)
+	}
+	env.Lister = fakeLister{}
+	if got := runWait(env, args); got != ExitOK {
+		t.Errorf("wait --free on a free port: exit %d, want %d", got, ExitOK)
+	}
+}
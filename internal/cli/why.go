@@ -0,0 +1,166 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/javiercepeda/tapas/internal/ports"
+)
+
+// maxPeersShown keeps the text output readable for busy servers; --json lists every peer.
+const maxPeersShown = 8
+
+// runWhy implements "tapas why": the whole story of what holds a port and how to stop it.
+func runWhy(env Env, args []string) int {
+	fs := newFlagSet("why", "[--json] PORT")
+	asJSON := fs.Bool("json", false, "print a JSON array with one object per listening process")
+	if code, ok := parseFlags(fs, args); !ok {
+		return code
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return ExitUsage
+	}
+	portNums, err := parsePorts(fs.Args())
+	if err != nil {
+		errorf("why", "%v", err)
+		return ExitUsage
+	}
+	port := portNums[0]
+	list, err := env.Lister.List()
+	if err != nil {
+		return errorf("why", "%v", err)
+	}
+	targets := listenersOn(list, port)
+	if len(targets) == 0 {
+		fmt.Fprintf(os.Stderr, "Nothing listens on port %d.\n", port)
+		return ExitAlreadyFree
+	}
+	explanations := make([]ports.Explanation, len(targets))
+	for i, p := range targets {
+		explanations[i] = ports.Explain(p)
+	}
+	if *asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(explanations); err != nil {
+			return errorf("why", "%v", err)
+		}
+		return ExitOK
+	}
+	for i, e := range explanations {
+		if i > 0 {
+			fmt.Println()
+		}
+		if err := writeExplanation(os.Stdout, e); err != nil {
+			return errorf("why", "%v", err)
+		}
+	}
+	return ExitOK
+}
+
+// writeExplanation prints one listener as labelled lines, skipping what is unknown.
+func writeExplanation(w io.Writer, e ports.Explanation) error {
+	fmt.Fprintf(w, "Port %d/%s is held by %s (PID %s)\n\n", e.Port, e.Protocol, e.Label, pidLabel(e.PID))
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	row := func(label, value string) {
+		if value != "" {
+			fmt.Fprintf(tw, "  %s\t%s\n", label, value)
+		}
+	}
+	bind := e.Bind
+	if e.Public {
+		bind = fmt.Sprintf("%s (%s): reachable from other machines", e.Bind, ports.OrDash(e.BindAddress))
+	} else if e.Bind != e.BindAddress {
+		bind = fmt.Sprintf("%s (%s)", e.Bind, e.BindAddress)
+	}
+	row("Bind", bind)
+	if e.Fix != nil {
+		row("Fix", fmt.Sprintf("%s: %s", e.Fix.How, e.Fix.Fix))
+	}
+	project := e.Project
+	if e.GitBranch != "" {
+		project = fmt.Sprintf("%s, branch %s", ports.OrDash(project), e.GitBranch)
+	}
+	row("Project", project)
+	row("Directory", e.WorkingDir)
+	row("Command", e.Command)
+	launch := e.Environment
+	if e.Framework != "" {
+		launch = strings.TrimSpace(e.Framework + " via " + ports.OrDash(e.Environment))
+	}
+	row("Launched", launch)
+	if len(e.Ancestry) > 0 {
+		chain := make([]string, len(e.Ancestry))
+		for i, a := range e.Ancestry {
+			chain[i] = fmt.Sprintf("%s (%d)", a.Name, a.PID)
+		}
+		row("Parents", strings.Join(chain, " ← "))
+	}
+	if e.Launcher != nil {
+		row("Launcher", fmt.Sprintf("%s (%d) may restart it", e.Launcher.Name, e.Launcher.PID))
+	}
+	if e.StartTime != nil {
+		row("Up", fmt.Sprintf("%s (since %s)", e.Uptime, e.StartTime.Local().Format("2006-01-02 15:04")))
+	}
+	if e.Container != "" {
+		row("Container", strings.TrimSpace(e.Container+" "+wrapParens(e.Image)))
+	} else if e.InDocker {
+		row("Container", "runs inside a container")
+	}
+	if e.SystemdUnit != "" {
+		unit := e.SystemdUnit
+		if e.UserUnit {
+			unit += " (user)"
+		}
+		row("Unit", unit)
+	}
+	row("Supervisor", e.Supervisor)
+	if e.Stopped {
+		row("State", "stopped (SIGSTOP): holds the port but does not serve")
+	}
+	row("Connected", peerSummary(e))
+	for _, r := range e.Stale {
+		row("Stale", r.Description())
+	}
+	row("Policy", e.Policy)
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	_, err := fmt.Fprintf(w, "\nTo stop it: %s\n  %s\n", e.Stop.How, e.Stop.Command)
+	return err
+}
+
+// peerSummary lists connected clients, naming local ones: "2: 127.0.0.1:52311 (curl 812), 10.0.0.4:61000".
+func peerSummary(e ports.Explanation) string {
+	if len(e.Peers) == 0 {
+		if e.Connections > 0 {
+			return fmt.Sprintf("%d (peers not visible)", e.Connections)
+		}
+		return "nobody"
+	}
+	var parts []string
+	for i, p := range e.Peers {
+		if i == maxPeersShown {
+			parts = append(parts, fmt.Sprintf("and %d more", len(e.Peers)-maxPeersShown))
+			break
+		}
+		s := p.Address
+		if p.Process != "" {
+			s += fmt.Sprintf(" (%s %d)", p.Process, p.PID)
+		}
+		parts = append(parts, s)
+	}
+	return fmt.Sprintf("%d: %s", len(e.Peers), strings.Join(parts, ", "))
+}
+
+func wrapParens(s string) string {
+	if s == "" {
+		return ""
+	}
+	return "(" + s + ")"
+}
@@ -0,0 +1,198 @@
+// Package config loads the optional user configuration file (JSON).
+// A missing file is not an error: TAPAS works with zero configuration.
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Config is the user configuration, read from config.json in the TAPAS config dir.
+type Config struct {
+	// RedactPatterns are extra regular expressions whose matches are hidden in command lines.
+	// Use a named group (?P<secret>...) to hide only part of the match.
+	RedactPatterns []string `json:"redact_patterns"`
+
+	Kill KillConfig `json:"kill"`
+
+	Policy PolicyConfig `json:"policy"`
+
+	Audit AuditConfig `json:"audit"`
+
+	Suggest SuggestConfig `json:"suggest"`
+
+	Exposure ExposureConfig `json:"exposure"`
+
+	History HistoryConfig `json:"history"`
+}
+
+// HistoryConfig controls the listener history read by tapas history: when listeners were first
+// and last seen, PID changes and connection peaks.
+type HistoryConfig struct {
+	// Enabled turns recording on; it happens whenever ports are listed (UI, tapas list, tapas prune).
+	Enabled bool `json:"enabled"`
+	// MaxSizeKB rotates history.jsonl once it would grow past this size (default 1024).
+	MaxSizeKB int `json:"max_size_kb"`
+	// Files is how many rotated files to keep (default 3).
+	Files int `json:"files"`
+}
+
+// HistoryPath returns the history file, or "" when history is off.
+func (c Config) HistoryPath() string {
+	if !c.History.Enabled {
+		return ""
+	}
+	if dir := StateDir(); dir != "" {
+		return filepath.Join(dir, "history.jsonl")
+	}
+	return ""
+}
+
+// ExposureConfig configures tapas audit.
+type ExposureConfig struct {
+	// Allow lists the expected listeners (same syntax as policy rules). When set, every other
+	// listener is reported as unknown.
+	Allow RuleConfig `json:"allow"`
+	// Ignore are rule IDs to skip, e.g. "public-dev-server".
+	Ignore []string `json:"ignore"`
+}
+
+// SuggestConfig controls tapas port suggest.
+type SuggestConfig struct {
+	// Range is where suggestions come from, e.g. "4000-4999". Empty uses 3000-9999.
+	Range string `json:"range"`
+	// Repos are directories (globs; ~ is expanded) whose declared ports are never suggested,
+	// e.g. "~/src/*". The current directory's own declared ports stay candidates.
+	Repos []string `json:"repos"`
+	// Reserved are ports ("5432") or ranges ("6000-6100") that are never suggested.
+	Reserved []string `json:"reserved"`
+}
+
+// AuditConfig controls the append-only log of signals, container actions and relaunches.
+type AuditConfig struct {
+	// Path overrides the log location (default audit.jsonl in the state dir, private to each
+	// user: the directory is created 0700 and the file 0600). To see what everyone on a jump host
+	// did, point it at a shared file that an admin has created group-writable beforehand (e.g.
+	// 0660 in a setgid directory); TAPAS appends to an existing file without changing its mode.
+	Path string `json:"path"`
+	// Disabled turns the audit log off.
+	Disabled bool `json:"disabled"`
+}
+
+// AuditPath returns the audit log path, or "" when auditing is disabled.
+func (c Config) AuditPath() string {
+	if c.Audit.Disabled {
+		return ""
+	}
+	if c.Audit.Path != "" {
+		return c.Audit.Path
+	}
+	if dir := StateDir(); dir != "" {
+		return filepath.Join(dir, "audit.jsonl")
+	}
+	return ""
+}
+
+// PolicyConfig guards kill actions. Deny rules can never be overridden from the UI;
+// protected listeners require typing the port number to confirm.
+type PolicyConfig struct {
+	// ReadOnly disables every kill, signal and container action (same as --read-only).
+	ReadOnly  bool       `json:"read_only"`
+	Protected RuleConfig `json:"protected"`
+	Deny      RuleConfig `json:"deny"`
+}
+
+// RuleConfig lists patterns per attribute: ports as "5432" or "8000-8100"; processes,
+// images ("postgres:*") and systemd units ("*.service") as case-insensitive globs.
+type RuleConfig struct {
+	Ports     []string `json:"ports"`
+	Processes []string `json:"processes"`
+	Images    []string `json:"images"`
+	Units     []string `json:"units"`
+}
+
+// KillConfig controls the graceful kill flow.
+type KillConfig struct {
+	// GracePeriod is how long to wait for exit and port release after SIGTERM (e.g. "5s"). Zero uses the default.
+	GracePeriod Duration `json:"grace_period"`
+	// AutoEscalate sends SIGKILL without asking when the process outlives the grace period.
+	AutoEscalate bool `json:"auto_escalate"`
+}
+
+// Duration is a time.Duration written as a string in JSON ("5s", "1m30s").
+type Duration time.Duration
+
+// UnmarshalJSON parses a duration string such as "5s".
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("duration must be a string like \"5s\": %w", err)
+	}
+	v, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	*d = Duration(v)
+	return nil
+}
+
+// Dir returns the TAPAS config directory: $XDG_CONFIG_HOME/tapas, else ~/.config/tapas.
+func Dir() string {
+	if d := os.Getenv("XDG_CONFIG_HOME"); d != "" {
+		return filepath.Join(d, "tapas")
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".config", "tapas")
+}
+
+// StateDir returns where TAPAS keeps state between runs (relaunch records, logs):
+// $XDG_STATE_HOME/tapas, else ~/.local/state/tapas.
+func StateDir() string {
+	if d := os.Getenv("XDG_STATE_HOME"); d != "" {
+		return filepath.Join(d, "tapas")
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".local", "state", "tapas")
+}
+
+// Path returns the config file path. TAPAS_CONFIG overrides the default location.
+func Path() string {
+	if p := os.Getenv("TAPAS_CONFIG"); p != "" {
+		return p
+	}
+	dir := Dir()
+	if dir == "" {
+		return ""
+	}
+	return filepath.Join(dir, "config.json")
+}
+
+// Load reads the config file. Returns a zero Config (and no error) when the file does not exist.
+func Load() (Config, error) {
+	var cfg Config
+	path := Path()
+	if path == "" {
+		return cfg, nil
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return cfg, nil
+	}
+	if err != nil {
+		return cfg, err
+	}
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return cfg, fmt.Errorf("%s: %w", path, err)
+	}
+	return cfg, nil
+}
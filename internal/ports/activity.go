@@ -0,0 +1,123 @@
+package ports
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// Activity is what TAPAS observed about one listener across listings: when it was first and last
+// seen, and the last time it had connections. Idle means no connections at any observation, so
+// idle times are only as good as how often something (the TUI, tapas list, tapas prune) listed ports.
+type Activity struct {
+	PID        int       `json:"pid"`
+	Port       uint16    `json:"port"`
+	FirstSeen  time.Time `json:"first_seen"`
+	LastSeen   time.Time `json:"last_seen"`
+	LastActive time.Time `json:"last_active,omitempty"` // zero: never seen with connections
+
+	// For the history log (SetHistoryLog): who it was, and the most connections observed.
+	Process    string `json:"process,omitempty"`
+	Project    string `json:"project,omitempty"`
+	Peak       int    `json:"peak,omitempty"`
+	PeakLogged int    `json:"peak_logged,omitempty"`
+}
+
+// IdleFor returns how long the listener has had no connections as far as TAPAS observed.
+func (a Activity) IdleFor(now time.Time) time.Duration {
+	since := a.LastActive
+	if since.IsZero() {
+		since = a.FirstSeen
+	}
+	return now.Sub(since)
+}
+
+// activityKey identifies a listener across listings: same process (PID and start time) on the same port.
+func activityKey(p Port) string {
+	return fmt.Sprintf("%d/%d/%d", p.PID, p.StartTime.Unix(), p.PortNum)
+}
+
+// ActivityStore keeps Activity per listener in activity.json in the state dir.
+type ActivityStore struct {
+	path string
+	mu   sync.Mutex
+}
+
+// NewActivityStore returns a store backed by activity.json in dir.
+func NewActivityStore(dir string) *ActivityStore {
+	return &ActivityStore{path: filepath.Join(dir, "activity.json")}
+}
+
+// ActivityIndex is the activity of every listener in one listing.
+type ActivityIndex map[string]Activity
+
+// Lookup returns the activity of p.
+func (idx ActivityIndex) Lookup(p Port) (Activity, bool) {
+	a, ok := idx[activityKey(p)]
+	return a, ok
+}
+
+// Observe records one listing and returns the updated activity of every listed port.
+// Listeners that are gone are dropped, so the file only ever describes what is running;
+// with a history log set, what changed since the previous listing is appended to it.
+func (s *ActivityStore) Observe(list []Port) (ActivityIndex, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	known := make(ActivityIndex)
+	data, err := os.ReadFile(s.path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+	if len(data) > 0 {
+		if err := json.Unmarshal(data, &known); err != nil {
+			known = make(ActivityIndex) // corrupt state is not worth failing a listing over
+		}
+	}
+	now := time.Now().Truncate(time.Second)
+	current := make(ActivityIndex, len(list))
+	for _, p := range list {
+		if p.PID <= 0 {
+			continue
+		}
+		key := activityKey(p)
+		a, ok := current[key]
+		if !ok {
+			a, ok = known[key]
+		}
+		if !ok {
+			a = Activity{PID: p.PID, Port: p.PortNum, FirstSeen: now}
+		}
+		a.LastSeen = now
+		if p.ConnectionCount > 0 {
+			a.LastActive = now
+		}
+		a.Process, a.Project = p.Process, projectName(p)
+		if p.ConnectionCount > a.Peak {
+			a.Peak = p.ConnectionCount
+		}
+		current[key] = a
+	}
+	if HistoryLogPath() != "" {
+		recordHistory(historyEvents(known, current, list, now))
+	}
+	if err := writeFileAtomic(s.path, current); err != nil {
+		return nil, err
+	}
+	return current, nil
+}
+
+// writeFileAtomic writes v as JSON to path via a temp file and rename (mode 0600, dir 0700).
+func writeFileAtomic(path string, v any) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	return WriteFileAtomic(path, append(data, '\n'), 0o600)
+}
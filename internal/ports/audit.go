@@ -0,0 +1,199 @@
+package ports
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"os"
+	"os/user"
+	"path/filepath"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// Audit actions.
+const (
+	AuditSignal    = "signal"
+	AuditContainer = "container"
+	AuditRelaunch  = "relaunch"
+)
+
+// AuditEntry is one line of the audit log: who did what to which listener, and how it went.
+type AuditEntry struct {
+	Time     time.Time `json:"time"`
+	User     string    `json:"user"`
+	Action   string    `json:"action"`           // signal, container, relaunch
+	Signal   string    `json:"signal,omitempty"` // SIGTERM, ... or the container action (stop, restart, ...)
+	Port     uint16    `json:"port,omitempty"`
+	PID      int       `json:"pid,omitempty"`
+	Process  string    `json:"process,omitempty"`
+	Command  string    `json:"command,omitempty"` // redacted
+	OK       bool      `json:"ok"`
+	Result   string    `json:"result"` // "ok" or the error
+	Snapshot *Record   `json:"snapshot,omitempty"`
+}
+
+var (
+	auditMu   sync.Mutex
+	auditPath string
+)
+
+// SetAuditLog sets the append-only JSON-lines file every signal, container action and relaunch
+// is recorded in. Empty disables auditing.
+func SetAuditLog(path string) {
+	auditMu.Lock()
+	auditPath = path
+	auditMu.Unlock()
+}
+
+// AuditLogPath returns the configured audit log ("" when disabled).
+func AuditLogPath() string {
+	auditMu.Lock()
+	defer auditMu.Unlock()
+	return auditPath
+}
+
+// auditUser names the acting user; under sudo the invoking user is what people ask about.
+func auditUser() string {
+	name := ""
+	if u, err := user.Current(); err == nil {
+		name = u.Username
+	}
+	if sudo := os.Getenv("SUDO_USER"); sudo != "" && sudo != name {
+		return sudo + " (as " + name + ")"
+	}
+	return name
+}
+
+// audit appends e to the log. Best effort: a full disk must not block a kill.
+func audit(e AuditEntry) {
+	auditMu.Lock()
+	defer auditMu.Unlock()
+	if auditPath == "" {
+		return
+	}
+	e.Time = time.Now()
+	e.User = auditUser()
+	data, err := json.Marshal(e)
+	if err != nil {
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(auditPath), 0o700); err != nil {
+		return
+	}
+	f, err := os.OpenFile(auditPath, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o600)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+	f.Write(append(data, '\n')) // one write per line: O_APPEND keeps concurrent writers from interleaving
+}
+
+// auditEntryFor fills the target fields of an entry from the Port snapshot.
+func auditEntryFor(p Port, action, signal string, err error) AuditEntry {
+	rec := NewRecord(p)
+	e := AuditEntry{
+		Action:   action,
+		Signal:   signal,
+		Port:     p.PortNum,
+		PID:      p.PID,
+		Process:  p.Process,
+		Command:  rec.Command,
+		OK:       err == nil,
+		Result:   "ok",
+		Snapshot: &rec,
+	}
+	if err != nil {
+		e.Result = signalErrorMessage(err)
+	}
+	return e
+}
+
+func auditSignal(p Port, sig syscall.Signal, err error) {
+	audit(auditEntryFor(p, AuditSignal, LookupSignal(sig).Name, err))
+}
+
+func auditContainer(p Port, action ContainerAction, r KillResult) {
+	e := auditEntryFor(p, AuditContainer, action.String(), nil)
+	e.OK = r.OK
+	if !r.OK {
+		e.Result = r.Error
+	}
+	audit(e)
+}
+
+func auditRelaunch(r RelaunchRecord, pid int, err error) {
+	e := AuditEntry{
+		Action:  AuditRelaunch,
+		Port:    r.Port,
+		PID:     pid,
+		Process: r.Process,
+		Command: r.CommandLine(),
+		OK:      err == nil,
+		Result:  "ok",
+	}
+	if err != nil {
+		e.Result = err.Error()
+	}
+	audit(e)
+}
+
+// ReadAuditLog reads every entry in path, oldest first. A line cut short by a crash is skipped.
+func ReadAuditLog(path string) ([]AuditEntry, error) {
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	var out []AuditEntry
+	sc := bufio.NewScanner(f)
+	sc.Buffer(make([]byte, 64*1024), 4*1024*1024)
+	for sc.Scan() {
+		var e AuditEntry
+		if json.Unmarshal(sc.Bytes(), &e) == nil {
+			out = append(out, e)
+		}
+	}
+	return out, sc.Err()
+}
+
+// AuditFilter selects audit entries. Zero fields match everything.
+type AuditFilter struct {
+	Port       uint16
+	PID        int
+	User       string // substring, case-insensitive
+	Process    string // substring of process or command, case-insensitive
+	Action     string
+	Since      time.Time
+	FailedOnly bool
+}
+
+// Match reports whether e passes every set field of f.
+func (f AuditFilter) Match(e AuditEntry) bool {
+	switch {
+	case f.Port != 0 && e.Port != f.Port:
+		return false
+	case f.PID != 0 && e.PID != f.PID:
+		return false
+	case f.Action != "" && e.Action != f.Action:
+		return false
+	case !f.Since.IsZero() && e.Time.Before(f.Since):
+		return false
+	case f.FailedOnly && e.OK:
+		return false
+	case f.User != "" && !strings.Contains(strings.ToLower(e.User), strings.ToLower(f.User)):
+		return false
+	}
+	if f.Process != "" {
+		q := strings.ToLower(f.Process)
+		if !strings.Contains(strings.ToLower(e.Process), q) && !strings.Contains(strings.ToLower(e.Command), q) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,44 @@
+package ports
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestAuditLogRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	SetAuditLog(path)
+	defer SetAuditLog("")
+
+	auditSignal(Port{PortNum: 8443, PID: 4242, Process: "ssh", Command: "ssh -N -L 8443:db:443 --password=hunter2"}, 15, nil)
+	auditSignal(Port{PortNum: 3000, PID: 4343, Process: "node"}, 9, errIdentityChanged)
+	// A line cut short by a crash must not hide the rest of the log.
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.WriteString(`{"time":"2026-`)
+	f.Close()
+
+	entries, err := ReadAuditLog(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("ReadAuditLog = %d entries, want 2", len(entries))
+	}
+	if e := entries[0]; e.Signal != "SIGTERM" || !e.OK || e.Snapshot == nil || e.Command != "ssh -N -L 8443:db:443 --password=***" {
+		t.Errorf("entry = %+v", e)
+	}
+	got := 0
+	for _, e := range entries {
+		if (AuditFilter{Port: 3000, FailedOnly: true, Since: time.Now().Add(-time.Minute)}).Match(e) {
+			got++
+		}
+	}
+	if got != 1 {
+		t.Errorf("filter matched %d entries, want 1", got)
+	}
+}
@@ -5,7 +5,6 @@ package ports
 import (
 	"bufio"
 	"os/exec"
-	"strconv"
 	"strings"
 )
 
@@ -0,0 +1,174 @@
+package ports
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// DiffSchema identifies the tapas diff --json document.
+const DiffSchema = "tapas/diff/v1"
+
+// SnapshotDiff is what changed between two snapshots. Listeners are matched by port, protocol and
+// identity (container name, else project and process), never by PID, so snapshots from another
+// machine or before a restart compare cleanly.
+type SnapshotDiff struct {
+	Added   []Record         `json:"added"`
+	Removed []Record         `json:"removed"`
+	Moved   []ListenerChange `json:"moved"`   // same project or container, different port
+	Changed []ListenerChange `json:"changed"` // same listener, different bind, exposure or image
+}
+
+// ListenerChange pairs a listener before and after, with what differs in words.
+type ListenerChange struct {
+	Before  Record   `json:"before"`
+	After   Record   `json:"after"`
+	Changes []string `json:"changes,omitempty"` // e.g. "bind 127.0.0.1 → 0.0.0.0 (now public)"
+}
+
+// Empty reports whether the snapshots describe the same listeners.
+func (d SnapshotDiff) Empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Moved) == 0 && len(d.Changed) == 0
+}
+
+// ReadSnapshot decodes a snapshot: the versioned document of tapas list -o json and the UI export,
+// or a bare array of records (tapas why --json, jq output).
+func ReadSnapshot(r io.Reader) (Snapshot, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return Snapshot{}, err
+	}
+	data = bytes.TrimSpace(data)
+	var s Snapshot
+	if bytes.HasPrefix(data, []byte("[")) {
+		err = json.Unmarshal(data, &s.Ports)
+	} else {
+		err = json.Unmarshal(data, &s)
+		if err == nil && s.Schema != "" && s.Schema != ExportSchema {
+			err = fmt.Errorf("unsupported schema %q (want %s)", s.Schema, ExportSchema)
+		}
+	}
+	if err != nil {
+		return Snapshot{}, err
+	}
+	return s, nil
+}
+
+// diffEntry is one listener of a snapshot; IPv4 and IPv6 sockets of the same listener are merged.
+type diffEntry struct {
+	Record
+	binds  []string
+	public bool
+}
+
+// identity names a listener independently of its PID and port.
+func identity(r Record) string {
+	if r.Container != "" {
+		return "container " + r.Container
+	}
+	return r.Project + "/" + r.Process
+}
+
+// movable reports whether the identity is specific enough to follow across ports.
+func movable(r Record) bool {
+	return r.Container != "" || r.Project != ""
+}
+
+func collapse(records []Record) (map[string]*diffEntry, []string) {
+	entries := make(map[string]*diffEntry)
+	var keys []string
+	for _, r := range records {
+		key := fmt.Sprintf("%d/%s/%s", r.Port, r.Protocol, identity(r))
+		e, ok := entries[key]
+		if !ok {
+			e = &diffEntry{Record: r}
+			entries[key] = e
+			keys = append(keys, key)
+		}
+		if bind := orAny(r.BindAddress); !containsString(e.binds, bind) {
+			e.binds = append(e.binds, bind)
+			sort.Strings(e.binds)
+		}
+		e.public = e.public || r.Public
+	}
+	return entries, keys
+}
+
+// DiffSnapshots compares before and after.
+func DiffSnapshots(before, after Snapshot) SnapshotDiff {
+	d := SnapshotDiff{Added: []Record{}, Removed: []Record{}, Moved: []ListenerChange{}, Changed: []ListenerChange{}}
+	old, oldKeys := collapse(before.Ports)
+	cur, curKeys := collapse(after.Ports)
+	var removed, added []*diffEntry
+	for _, key := range oldKeys {
+		a := old[key]
+		b, ok := cur[key]
+		if !ok {
+			removed = append(removed, a)
+			continue
+		}
+		if changes := entryChanges(a, b); len(changes) > 0 {
+			d.Changed = append(d.Changed, ListenerChange{Before: a.Record, After: b.Record, Changes: changes})
+		}
+	}
+	for _, key := range curKeys {
+		if _, ok := old[key]; !ok {
+			added = append(added, cur[key])
+		}
+	}
+	for _, a := range removed {
+		moved := -1
+		for i, b := range added {
+			if b != nil && movable(a.Record) && identity(a.Record) == identity(b.Record) && a.Protocol == b.Protocol {
+				moved = i
+				break
+			}
+		}
+		if moved < 0 {
+			d.Removed = append(d.Removed, a.Record)
+			continue
+		}
+		b := added[moved]
+		added[moved] = nil
+		changes := append([]string{fmt.Sprintf("port %d → %d", a.Port, b.Port)}, entryChanges(a, b)...)
+		d.Moved = append(d.Moved, ListenerChange{Before: a.Record, After: b.Record, Changes: changes})
+	}
+	for _, b := range added {
+		if b != nil {
+			d.Added = append(d.Added, b.Record)
+		}
+	}
+	sortRecords := func(rs []Record) {
+		sort.SliceStable(rs, func(i, j int) bool { return rs[i].Port < rs[j].Port })
+	}
+	sortChanges := func(cs []ListenerChange) {
+		sort.SliceStable(cs, func(i, j int) bool { return cs[i].Before.Port < cs[j].Before.Port })
+	}
+	sortRecords(d.Added)
+	sortRecords(d.Removed)
+	sortChanges(d.Moved)
+	sortChanges(d.Changed)
+	return d
+}
+
+// entryChanges describes bind, exposure and image differences between two matched listeners.
+func entryChanges(a, b *diffEntry) []string {
+	var changes []string
+	if before, after := strings.Join(a.binds, ","), strings.Join(b.binds, ","); before != after {
+		c := fmt.Sprintf("bind %s → %s", before, after)
+		switch {
+		case b.public && !a.public:
+			c += " (now public)"
+		case a.public && !b.public:
+			c += " (no longer public)"
+		}
+		changes = append(changes, c)
+	}
+	if a.Image != b.Image {
+		changes = append(changes, fmt.Sprintf("image %s → %s", OrDash(a.Image), OrDash(b.Image)))
+	}
+	return changes
+}
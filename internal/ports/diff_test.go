@@ -0,0 +1,53 @@
+package ports
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDiffSnapshots(t *testing.T) {
+	before := Snapshot{Ports: []Record{
+		{Port: 5432, Protocol: "tcp", Process: "docker-proxy", Container: "db", Image: "postgres:15", BindAddress: "127.0.0.1"},
+		{Port: 3000, Protocol: "tcp", Process: "node", Project: "shop", BindAddress: "127.0.0.1"},
+		{Port: 8080, Protocol: "tcp", Process: "java", BindAddress: "0.0.0.0", Public: true},
+		{Port: 22, Protocol: "tcp", Process: "sshd", BindAddress: "0.0.0.0", Public: true},
+		{Port: 22, Protocol: "tcp", Process: "sshd", BindAddress: "::", Public: true},
+	}}
+	after := Snapshot{Ports: []Record{
+		{Port: 5432, Protocol: "tcp", Process: "docker-proxy", Container: "db", Image: "postgres:16", BindAddress: "0.0.0.0", Public: true},
+		{Port: 3001, Protocol: "tcp", Process: "node", Project: "shop", BindAddress: "127.0.0.1"},
+		{Port: 5173, Protocol: "tcp", Process: "node", Project: "admin", BindAddress: "127.0.0.1"},
+		{Port: 22, Protocol: "tcp", Process: "sshd", BindAddress: "::", Public: true},
+		{Port: 22, Protocol: "tcp", Process: "sshd", BindAddress: "0.0.0.0", Public: true},
+	}}
+	d := DiffSnapshots(before, after)
+	if len(d.Added) != 1 || d.Added[0].Port != 5173 {
+		t.Errorf("added: %+v", d.Added)
+	}
+	if len(d.Removed) != 1 || d.Removed[0].Port != 8080 {
+		t.Errorf("removed: %+v", d.Removed)
+	}
+	if len(d.Moved) != 1 || d.Moved[0].Before.Port != 3000 || d.Moved[0].After.Port != 3001 {
+		t.Errorf("moved: %+v", d.Moved)
+	}
+	if len(d.Changed) != 1 {
+		t.Fatalf("changed: %+v", d.Changed)
+	}
+	got := strings.Join(d.Changed[0].Changes, "; ")
+	if want := "bind 127.0.0.1 → 0.0.0.0 (now public); image postgres:15 → postgres:16"; got != want {
+		t.Errorf("changes = %q, want %q", got, want)
+	}
+	if !DiffSnapshots(after, after).Empty() {
+		t.Error("a snapshot differs from itself")
+	}
+}
+
+func TestReadSnapshot(t *testing.T) {
+	s, err := ReadSnapshot(strings.NewReader(`[{"port": 3000, "protocol": "tcp", "process": "node"}]`))
+	if err != nil || len(s.Ports) != 1 || s.Ports[0].Port != 3000 {
+		t.Errorf("array: %+v, %v", s, err)
+	}
+	if _, err := ReadSnapshot(strings.NewReader(`{"schema": "tapas/ports/v9", "ports": []}`)); err == nil {
+		t.Error("unknown schema accepted")
+	}
+}
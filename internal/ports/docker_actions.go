@@ -0,0 +1,87 @@
+package ports
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// ContainerAction is a container-native action for rows backed by a Docker container.
+// For these rows the host PID is usually docker-proxy (or nothing), so signalling it does not stop the container.
+type ContainerAction int
+
+const (
+	ContainerStop ContainerAction = iota
+	ContainerRestart
+	ContainerPause
+	ContainerUnpause
+)
+
+func (a ContainerAction) String() string {
+	switch a {
+	case ContainerRestart:
+		return "restart"
+	case ContainerPause:
+		return "pause"
+	case ContainerUnpause:
+		return "unpause"
+	default:
+		return "stop"
+	}
+}
+
+// PastTense is used in result messages ("Container api stopped.").
+func (a ContainerAction) PastTense() string {
+	switch a {
+	case ContainerRestart:
+		return "restarted"
+	case ContainerPause:
+		return "paused"
+	case ContainerUnpause:
+		return "unpaused"
+	default:
+		return "stopped"
+	}
+}
+
+// RunContainerAction runs docker stop/restart/pause/unpause for the container and waits for it.
+// docker stop can take up to the container's stop timeout (10s by default); run it off the UI goroutine.
+// Deny verdicts of the installed policy are refused. Every attempt is audited.
+func RunContainerAction(p Port, action ContainerAction) KillResult {
+	if p.DockerContainerName == "" {
+		return KillResult{OK: false, Error: "no container for this port"}
+	}
+	r := runContainerAction(p, action)
+	auditContainer(p, action, r)
+	return r
+}
+
+func runContainerAction(p Port, action ContainerAction) KillResult {
+	name := p.DockerContainerName
+	if err := enforcePolicy(p); err != nil {
+		return KillResult{OK: false, Error: err.Error()}
+	}
+	cmd := exec.Command("docker", action.String(), name)
+	cmd.Env = []string{"LC_ALL=C"}
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		msg := strings.TrimSpace(string(out))
+		if msg == "" {
+			msg = err.Error()
+		}
+		return KillResult{OK: false, Error: fmt.Sprintf("Failed to %s container %s (%s)", action, name, msg)}
+	}
+	return KillResult{OK: true}
+}
+
+// ContainerLogsCmd returns the command that follows the container's logs. It is meant to be
+// handed the terminal (tea.ExecProcess); the user leaves with Ctrl-C.
+func ContainerLogsCmd(name string) *exec.Cmd {
+	return exec.Command("docker", "logs", "--follow", "--tail", "200", name)
+}
+
+// ContainerShellCmd returns an interactive shell inside the container (bash when available, else sh),
+// meant to be handed the terminal (tea.ExecProcess).
+func ContainerShellCmd(name string) *exec.Cmd {
+	return exec.Command("docker", "exec", "-it", name, "sh", "-c", "command -v bash >/dev/null 2>&1 && exec bash || exec sh")
+}
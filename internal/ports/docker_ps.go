@@ -6,16 +6,22 @@ import (
 	"strings"
 )
 
-// dockerPortMap returns host port -> {container name, image} from docker ps.
+// dockerMapping is one published port of a running container.
+type dockerMapping struct {
+	Name, Image   string
+	ContainerPort uint16
+}
+
+// dockerPortMap returns host port -> {container name, image, container port} from docker ps.
 // Ignores errors (docker not installed or not running); returns nil map on failure.
-func dockerPortMap() map[uint16]struct{ Name, Image string } {
+func dockerPortMap() map[uint16]dockerMapping {
 	cmd := exec.Command("docker", "ps", "--format", "{{.Names}}\t{{.Image}}\t{{.Ports}}")
 	cmd.Env = []string{"LC_ALL=C"}
 	out, err := cmd.Output()
 	if err != nil {
 		return nil
 	}
-	m := make(map[uint16]struct{ Name, Image string })
+	m := make(map[uint16]dockerMapping)
 	lines := strings.Split(strings.TrimSpace(string(out)), "\n")
 	for _, line := range lines {
 		line = strings.TrimSpace(line)
@@ -33,7 +39,7 @@ func dockerPortMap() map[uint16]struct{ Name, Image string } {
 			segment = strings.TrimSpace(segment)
 			hostPort := parseDockerHostPort(segment)
 			if hostPort > 0 {
-				m[hostPort] = struct{ Name, Image string }{Name: name, Image: image}
+				m[hostPort] = dockerMapping{Name: name, Image: image, ContainerPort: parseDockerContainerPort(segment)}
 			}
 		}
 	}
@@ -64,7 +70,22 @@ func parseDockerHostPort(segment string) uint16 {
 	return uint16(port)
 }
 
-// EnrichDocker fills DockerContainerName, DockerImage, and InDocker for ports that match docker ps mappings.
+// parseDockerContainerPort extracts the container port from a segment like "0.0.0.0:15432->5432/tcp".
+// Returns 0 if not parseable.
+func parseDockerContainerPort(segment string) uint16 {
+	i := strings.Index(segment, "->")
+	if i < 0 {
+		return 0
+	}
+	right, _, _ := strings.Cut(segment[i+2:], "/")
+	port, err := strconv.Atoi(strings.TrimSpace(right))
+	if err != nil || port <= 0 || port > 65535 {
+		return 0
+	}
+	return uint16(port)
+}
+
+// EnrichDocker fills DockerContainerName, DockerImage, DockerContainerPort, and InDocker for ports that match docker ps mappings.
 // Call after building the port list. No-op if docker is unavailable.
 func EnrichDocker(ports *[]Port) {
 	if ports == nil || len(*ports) == 0 {
@@ -79,6 +100,7 @@ func EnrichDocker(ports *[]Port) {
 		if info, ok := m[p.PortNum]; ok {
 			p.DockerContainerName = info.Name
 			p.DockerImage = info.Image
+			p.DockerContainerPort = info.ContainerPort
 			p.InDocker = true
 		}
 	}
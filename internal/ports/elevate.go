@@ -0,0 +1,106 @@
+package ports
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+// ElevatedSignalCmd returns the command that re-runs this binary as root to signal p:
+// sudo (or pkexec) tapas signal --pid N --sig S --expect-start T [--expect-exe PATH] [--port N]
+// [--deny RULES]. It is meant to be handed the terminal (tea.ExecProcess) so the password prompt
+// works. The helper re-verifies the target itself; nothing here is trusted as root. Under sudo it
+// reads root's config, so the installed deny rules are passed along for it to check as well.
+func ElevatedSignalCmd(p Port, sig syscall.Signal) (*exec.Cmd, error) {
+	if p.PID <= 0 {
+		return nil, errors.New("invalid pid")
+	}
+	if p.StartTime.IsZero() {
+		return nil, errors.New("start time unknown; cannot verify the process as root")
+	}
+	self, err := os.Executable()
+	if err != nil {
+		return nil, err
+	}
+	name := LookupSignal(sig).Name
+	if _, err := ParseSignal(name); err != nil {
+		name = strconv.Itoa(int(sig))
+	}
+	args := []string{self, "signal", "--pid", strconv.Itoa(p.PID), "--sig", name,
+		"--expect-start", strconv.FormatFloat(float64(p.StartTime.UnixNano())/1e9, 'f', 3, 64)}
+	if p.Executable != "" {
+		args = append(args, "--expect-exe", p.Executable)
+	}
+	if p.PortNum > 0 {
+		args = append(args, "--port", strconv.Itoa(int(p.PortNum)))
+	}
+	policyMu.RLock()
+	deny := activePolicy.Deny
+	policyMu.RUnlock()
+	if !deny.empty() {
+		data, err := json.Marshal(deny)
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, "--deny", string(data))
+	}
+	for _, tool := range []string{"sudo", "pkexec"} {
+		if path, err := exec.LookPath(tool); err == nil {
+			return exec.Command(path, args...), nil
+		}
+	}
+	return nil, errors.New("neither sudo nor pkexec is installed")
+}
+
+// VerifiedPort returns a snapshot of pid after checking it still has the expected start time
+// and, when given, executable. Used by the privileged helper, which trusts nothing from its caller:
+// Process is taken from the running executable so deny rules match as usual.
+func VerifiedPort(pid int, start time.Time, exe string) (Port, error) {
+	if pid <= 1 {
+		return Port{}, fmt.Errorf("refusing to signal PID %d", pid)
+	}
+	p := Port{PID: pid, StartTime: start, Executable: exe}
+	if err := verifyIdentity(p); err != nil {
+		return Port{}, errors.New(signalErrorMessage(err))
+	}
+	_, actual, _ := processIdentity(pid)
+	if actual != "" {
+		p.Executable = actual
+		p.Process = filepath.Base(actual)
+	}
+	return p, nil
+}
+
+// AwaitTermination waits for a process that was signalled elsewhere (the privileged helper)
+// to exit and for its port to be released, like the waiting half of Terminate.
+func AwaitTermination(p Port, grace time.Duration) TerminateResult {
+	if grace <= 0 {
+		grace = DefaultGracePeriod
+	}
+	start := time.Now()
+	r := TerminateResult{KillResult: KillResult{OK: true}, Processes: 1, Ports: []uint16{p.PortNum}}
+	r.Exited = WaitExit(p, grace)
+	r.Elapsed = time.Since(start)
+	if r.Exited {
+		r.PortFree = waitPortsFree(r.Ports, time.Now().Add(grace))
+	}
+	return r
+}
+
+// AuditElevated records a signal sent through the privileged helper in the user's audit log
+// (the helper logs to root's).
+func AuditElevated(p Port, sig syscall.Signal, err error) {
+	e := auditEntryFor(p, AuditSignal, LookupSignal(sig).Name, nil)
+	e.Result = "ok (as root)"
+	if err != nil {
+		e.OK = false
+		e.Result = "as root: " + err.Error()
+	}
+	audit(e)
+}
@@ -0,0 +1,29 @@
+//go:build darwin
+
+package ports
+
+import (
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// EphemeralRange returns the kernel's range for outgoing connections (net.inet.ip.portrange).
+func EphemeralRange() PortRange {
+	cmd := exec.Command("sysctl", "-n", "net.inet.ip.portrange.first", "net.inet.ip.portrange.last")
+	cmd.Env = []string{"LC_ALL=C"}
+	out, err := cmd.Output()
+	if err != nil {
+		return ianaEphemeralRange
+	}
+	fields := strings.Fields(string(out))
+	if len(fields) != 2 {
+		return ianaEphemeralRange
+	}
+	from, err1 := strconv.ParseUint(fields[0], 10, 16)
+	to, err2 := strconv.ParseUint(fields[1], 10, 16)
+	if err1 != nil || err2 != nil || from > to {
+		return ianaEphemeralRange
+	}
+	return PortRange{From: uint16(from), To: uint16(to)}
+}
@@ -0,0 +1,27 @@
+//go:build linux
+
+package ports
+
+import (
+	"os"
+	"strconv"
+	"strings"
+)
+
+// EphemeralRange returns the kernel's range for outgoing connections (net.ipv4.ip_local_port_range).
+func EphemeralRange() PortRange {
+	data, err := os.ReadFile("/proc/sys/net/ipv4/ip_local_port_range")
+	if err != nil {
+		return ianaEphemeralRange
+	}
+	fields := strings.Fields(string(data))
+	if len(fields) != 2 {
+		return ianaEphemeralRange
+	}
+	from, err1 := strconv.ParseUint(fields[0], 10, 16)
+	to, err2 := strconv.ParseUint(fields[1], 10, 16)
+	if err1 != nil || err2 != nil || from > to {
+		return ianaEphemeralRange
+	}
+	return PortRange{From: uint16(from), To: uint16(to)}
+}
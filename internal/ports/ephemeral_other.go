@@ -0,0 +1,8 @@
+//go:build !darwin && !linux
+
+package ports
+
+// EphemeralRange returns the IANA dynamic port range on platforms we cannot query.
+func EphemeralRange() PortRange {
+	return ianaEphemeralRange
+}
@@ -0,0 +1,125 @@
+package ports
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// ExportSchema identifies the JSON export format. Bump the version on breaking changes.
+const ExportSchema = "tapas/ports/v1"
+
+// Snapshot is the top-level JSON export document.
+type Snapshot struct {
+	Schema      string    `json:"schema"`
+	GeneratedAt time.Time `json:"generated_at"`
+	Host        string    `json:"host,omitempty"`
+	Ports       []Record  `json:"ports"`
+}
+
+// Record is the stable export shape of a Port. Command is always redacted.
+type Record struct {
+	Port          uint16        `json:"port"`
+	Protocol      string        `json:"protocol"`
+	PID           int           `json:"pid"`
+	PPID          int           `json:"ppid,omitempty"`
+	Process       string        `json:"process"`
+	Command       string        `json:"command,omitempty"`
+	WorkingDir    string        `json:"cwd,omitempty"`
+	Project       string        `json:"project,omitempty"`
+	Framework     string        `json:"framework,omitempty"`
+	Environment   string        `json:"environment,omitempty"`
+	BindAddress   string        `json:"bind_address,omitempty"`
+	Container     string        `json:"container,omitempty"`
+	Image         string        `json:"image,omitempty"`
+	InDocker      bool          `json:"in_docker,omitempty"`
+	Connections   int           `json:"connections"`
+	StartTime     *time.Time    `json:"start_time,omitempty"`
+	UptimeSeconds int64         `json:"uptime_seconds,omitempty"`
+	Stopped       bool          `json:"stopped,omitempty"`
+	Stale         []StaleReason `json:"stale,omitempty"`
+
+	// Derived fields: what the TUI shows in its columns.
+	Label  string `json:"label"`  // process column: "Next.js (node)", "PostgreSQL (LOCAL)", "Docker → api"
+	App    string `json:"app"`    // app badge: framework, well-known app, or "—"
+	Bind   string `json:"bind"`   // LOCAL, PUBLIC, or the address
+	Public bool   `json:"public"` // bound to all interfaces
+	Uptime string `json:"uptime"` // e.g. "3h"
+}
+
+// NewRecord converts a Port to its export shape.
+func NewRecord(p Port) Record {
+	r := Record{
+		Port:          p.PortNum,
+		Protocol:      p.Protocol,
+		PID:           p.PID,
+		PPID:          p.PPID,
+		Process:       p.Process,
+		Command:       p.SafeCommand(),
+		WorkingDir:    p.WorkingDir,
+		Project:       projectName(p),
+		Framework:     p.Framework,
+		Environment:   p.Environment,
+		BindAddress:   p.BindAddress,
+		Container:     p.DockerContainerName,
+		Image:         p.DockerImage,
+		InDocker:      p.InDocker,
+		Connections:   p.ConnectionCount,
+		UptimeSeconds: int64(p.Uptime().Seconds()),
+		Stopped:       p.Stopped,
+		Stale:         p.StaleReasons,
+		Label:         ProcessLabel(&p),
+		App:           AppBadge(&p),
+		Bind:          BindLabel(p.BindAddress),
+		Public:        IsPublicBind(p.BindAddress),
+		Uptime:        FormatUptime(p.Uptime()),
+	}
+	if !p.StartTime.IsZero() {
+		t := p.StartTime.Truncate(time.Second)
+		r.StartTime = &t
+	}
+	return r
+}
+
+// projectName is the detected project name, else the working directory's base name, else "".
+func projectName(p Port) string {
+	if p.ProjectDisplayName == "" && p.WorkingDir != "" {
+		return p.Project()
+	}
+	return p.ProjectDisplayName
+}
+
+// NewSnapshot wraps ports in a versioned export document.
+func NewSnapshot(list []Port) Snapshot {
+	host, _ := os.Hostname()
+	s := Snapshot{Schema: ExportSchema, GeneratedAt: time.Now().Truncate(time.Second), Host: host, Ports: []Record{}}
+	for _, p := range list {
+		s.Ports = append(s.Ports, NewRecord(p))
+	}
+	return s
+}
+
+// WriteJSON writes list as an indented, versioned JSON snapshot.
+func WriteJSON(w io.Writer, list []Port) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(NewSnapshot(list))
+}
+
+// ExportFile writes list as JSON to tapas-export-<timestamp>.json in dir and returns the path.
+func ExportFile(dir string, list []Port) (string, error) {
+	name := fmt.Sprintf("tapas-export-%s.json", time.Now().Format("20060102-150405"))
+	path := filepath.Join(dir, name)
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if err != nil {
+		return "", err
+	}
+	if err := WriteJSON(f, list); err != nil {
+		f.Close()
+		return "", err
+	}
+	return path, f.Close()
+}
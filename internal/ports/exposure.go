@@ -0,0 +1,207 @@
+package ports
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Severity of an exposure finding.
+type Severity string
+
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+)
+
+// ExposureRule is one check of tapas audit; check returns a message when p violates it.
+type ExposureRule struct {
+	ID       string
+	Title    string
+	Severity Severity
+	Help     string
+	check    func(p Port, opts ExposureOptions) string
+}
+
+// ExposureOptions configures AuditExposure.
+type ExposureOptions struct {
+	// Allow lists the expected listeners; when any pattern is set, every other listener is
+	// reported by unknown-listener.
+	Allow PolicyRules
+	// Ignore are rule IDs to skip.
+	Ignore []string
+}
+
+// Finding is one rule violation.
+type Finding struct {
+	Rule     string       `json:"rule"`
+	Severity Severity     `json:"severity"`
+	Message  string       `json:"message"`
+	Listener Record       `json:"listener"`
+	Fix      *Remediation `json:"fix,omitempty"` // for public-* rules, when the command line is recognised
+}
+
+// ExposureRules are the checks of tapas audit, in report order.
+var ExposureRules = []ExposureRule{
+	{
+		ID: "public-database", Title: "Database bound to all interfaces", Severity: SeverityError,
+		Help: "Bind the database to 127.0.0.1 (or publish it as 127.0.0.1:PORT:PORT in compose).",
+		check: func(p Port, _ ExposureOptions) string {
+			if db := databaseOf(p); db != "" && IsPublicBind(p.BindAddress) {
+				return fmt.Sprintf("%s on port %d listens on all interfaces (%s)", db, p.PortNum, orAny(p.BindAddress))
+			}
+			return ""
+		},
+	},
+	{
+		ID: "public-debug-port", Title: "Debugger reachable from the network", Severity: SeverityError,
+		Help: "A debug port allows arbitrary code execution; bind it to 127.0.0.1 (e.g. --inspect=127.0.0.1:9229).",
+		check: func(p Port, _ ExposureOptions) string {
+			if dbg := debuggerOf(p); dbg != "" && IsPublicBind(p.BindAddress) {
+				return fmt.Sprintf("%s on port %d listens on all interfaces (%s)", dbg, p.PortNum, orAny(p.BindAddress))
+			}
+			return ""
+		},
+	},
+	{
+		ID: "public-dev-server", Title: "Dev server exposed publicly", Severity: SeverityWarning,
+		Help: "Dev servers are not hardened; bind to localhost unless you test from another device on purpose.",
+		check: func(p Port, _ ExposureOptions) string {
+			if p.Framework != "" && IsPublicBind(p.BindAddress) {
+				return fmt.Sprintf("%s dev server on port %d listens on all interfaces (%s)", p.Framework, p.PortNum, orAny(p.BindAddress))
+			}
+			return ""
+		},
+	},
+	{
+		ID: "root-in-user-session", Title: "Listener running as root in a user session", Severity: SeverityWarning,
+		Help: "Run it as your user; use a port above 1024 or grant cap_net_bind_service instead of sudo.",
+		check: func(p Port, _ ExposureOptions) string {
+			if p.Security != nil && p.Security.EUID == 0 && p.SystemdUnit == "" && !p.InDocker && inUserSession(p.PID) {
+				return fmt.Sprintf("%s (PID %d) on port %d runs as root from a login session", p.Process, p.PID, p.PortNum)
+			}
+			return ""
+		},
+	},
+	{
+		ID: "unknown-listener", Title: "Listener not in the allowlist", Severity: SeverityWarning,
+		Help: "Add it to exposure.allow in the config if it is expected, or stop it.",
+		check: func(p Port, opts ExposureOptions) string {
+			if opts.Allow.empty() || opts.Allow.match(p) != "" {
+				return ""
+			}
+			return fmt.Sprintf("%s on port %d is not in the allowlist", ProcessLabel(&p), p.PortNum)
+		},
+	},
+}
+
+// AuditExposure checks every listener against ExposureRules. A process listening on both IPv4
+// and IPv6 is reported once per rule and port.
+func AuditExposure(list []Port, opts ExposureOptions) []Finding {
+	ignore := make(map[string]bool)
+	for _, id := range opts.Ignore {
+		ignore[id] = true
+	}
+	findings := []Finding{}
+	seen := make(map[string]bool)
+	for _, rule := range ExposureRules {
+		if ignore[rule.ID] {
+			continue
+		}
+		for _, p := range list {
+			msg := rule.check(p, opts)
+			key := fmt.Sprintf("%s/%d/%d", rule.ID, p.PID, p.PortNum)
+			if msg == "" || seen[key] {
+				continue
+			}
+			seen[key] = true
+			f := Finding{Rule: rule.ID, Severity: rule.Severity, Message: msg, Listener: NewRecord(p)}
+			if strings.HasPrefix(rule.ID, "public-") {
+				f.Fix = Remediate(p)
+			}
+			findings = append(findings, f)
+		}
+	}
+	return findings
+}
+
+// LookupExposureRule returns the rule with id.
+func LookupExposureRule(id string) (ExposureRule, bool) {
+	for _, r := range ExposureRules {
+		if r.ID == id {
+			return r, true
+		}
+	}
+	return ExposureRule{}, false
+}
+
+// databaseProcesses and databaseImages name databases running on non-default ports.
+var (
+	databaseProcesses = map[string]string{
+		"postgres": "PostgreSQL", "mysqld": "MySQL", "mariadbd": "MariaDB", "redis-server": "Redis",
+		"mongod": "Mongo", "memcached": "Memcached", "clickhouse-server": "ClickHouse",
+	}
+	databaseImages = []string{"postgres", "mysql", "mariadb", "redis", "mongo", "elasticsearch", "memcached", "clickhouse"}
+)
+
+// databaseOf names the database behind p by port (DatabaseProductName), process or Docker image.
+func databaseOf(p Port) string {
+	if name := DatabaseProductName(p.PortNum); name != "" {
+		return name
+	}
+	if name := databaseProcesses[strings.ToLower(p.Process)]; name != "" {
+		return name
+	}
+	image := strings.ToLower(p.DockerImage)
+	if i := strings.LastIndex(image, "/"); i >= 0 {
+		image = image[i+1:]
+	}
+	for _, db := range databaseImages {
+		if image == db || strings.HasPrefix(image, db+":") {
+			return db
+		}
+	}
+	return ""
+}
+
+// debugPorts are the defaults of common debuggers.
+var debugPorts = map[uint16]string{
+	9229: "Node inspector", 9222: "Chrome DevTools", 5005: "JDWP", 2345: "Delve", 5678: "debugpy",
+}
+
+// debugFlagRe finds a debugger's listen port in a command line: --inspect=0.0.0.0:9230,
+// -agentlib:jdwp=transport=dt_socket,server=y,address=*:8787, dlv --listen=:2346.
+var debugFlagRe = regexp.MustCompile(`(--inspect(?:-brk)?=|jdwp=\S*address=|--listen=)(?:\S*:)?(\d+)`)
+
+// debuggerOf names the debugger listening on p, by default port or by a flag naming its port.
+func debuggerOf(p Port) string {
+	if name := debugPorts[p.PortNum]; name != "" {
+		return name
+	}
+	for _, m := range debugFlagRe.FindAllStringSubmatch(p.Command, -1) {
+		if n, err := strconv.Atoi(m[2]); err != nil || n != int(p.PortNum) {
+			continue
+		}
+		switch {
+		case strings.HasPrefix(m[1], "--inspect"):
+			return "Node inspector"
+		case strings.HasPrefix(m[1], "jdwp"):
+			return "JDWP"
+		case strings.HasPrefix(strings.ToLower(p.Process), "dlv"):
+			return "Delve"
+		}
+	}
+	return ""
+}
+
+func (r PolicyRules) empty() bool {
+	return len(r.Ports) == 0 && len(r.Processes) == 0 && len(r.Images) == 0 && len(r.Units) == 0
+}
+
+func orAny(addr string) string {
+	if addr == "" {
+		return "*"
+	}
+	return addr
+}
@@ -0,0 +1,80 @@
+package ports
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestAuditExposure(t *testing.T) {
+	list := []Port{
+		{PID: 10, PortNum: 5432, Process: "postgres", BindAddress: "0.0.0.0"},
+		{PID: 10, PortNum: 5432, Process: "postgres", BindAddress: "::"}, // same socket pair, reported once
+		{PID: 11, PortNum: 15432, Process: "docker-proxy", DockerImage: "library/postgres:16", BindAddress: "0.0.0.0"},
+		{PID: 12, PortNum: 6379, Process: "redis-server", BindAddress: "127.0.0.1"},
+		{PID: 13, PortNum: 9230, Process: "node", Command: "node --inspect=0.0.0.0:9230 server.js", BindAddress: "0.0.0.0"},
+		{PID: 14, PortNum: 5173, Process: "node", Framework: "Vite", BindAddress: "0.0.0.0"},
+		{PID: 15, PortNum: 8080, Process: "java", Command: "java -agentlib:jdwp=transport=dt_socket,server=y,address=*:8787 -jar app.jar", BindAddress: "0.0.0.0"},
+	}
+	count := func(findings []Finding) map[string]int {
+		n := make(map[string]int)
+		for _, f := range findings {
+			n[f.Rule]++
+		}
+		return n
+	}
+
+	got := count(AuditExposure(list, ExposureOptions{}))
+	want := map[string]int{"public-database": 2, "public-debug-port": 1, "public-dev-server": 1}
+	for rule, n := range want {
+		if got[rule] != n {
+			t.Errorf("%s: %d findings, want %d (all: %v)", rule, got[rule], n, got)
+		}
+	}
+	if got["unknown-listener"] != 0 {
+		t.Errorf("unknown-listener without an allowlist: %d findings", got["unknown-listener"])
+	}
+
+	opts := ExposureOptions{Allow: PolicyRules{Processes: []string{"postgres", "redis-*", "docker-proxy"}}, Ignore: []string{"public-database"}}
+	got = count(AuditExposure(list, opts))
+	if got["public-database"] != 0 {
+		t.Errorf("ignored rule still reported: %v", got)
+	}
+	if got["unknown-listener"] != 3 { // two node processes and java
+		t.Errorf("unknown-listener: %d findings, want 3 (all: %v)", got["unknown-listener"], got)
+	}
+}
+
+func TestAuditExposureFix(t *testing.T) {
+	list := []Port{
+		{PID: 14, PortNum: 5173, Process: "node", Framework: "Vite", Command: "node node_modules/.bin/vite --host", BindAddress: "0.0.0.0"},
+		{PID: 20, PortNum: 4000, Process: "mystery", BindAddress: "0.0.0.0"},
+	}
+	opts := ExposureOptions{Allow: PolicyRules{Processes: []string{"node"}}}
+	data, err := json.Marshal(AuditExposure(list, opts))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var findings []struct {
+		Rule string       `json:"rule"`
+		Fix  *Remediation `json:"fix"`
+	}
+	if err := json.Unmarshal(data, &findings); err != nil {
+		t.Fatal(err)
+	}
+	for _, f := range findings {
+		switch f.Rule {
+		case "public-dev-server":
+			if f.Fix == nil || !strings.Contains(f.Fix.Fix, "--host 127.0.0.1") {
+				t.Errorf("public Vite: fix = %+v, want vite --host 127.0.0.1", f.Fix)
+			}
+		case "unknown-listener":
+			if f.Fix != nil {
+				t.Errorf("unknown-listener has a fix: %+v", f.Fix)
+			}
+		}
+	}
+	if len(findings) < 2 {
+		t.Errorf("findings: %s", data)
+	}
+}
@@ -0,0 +1,167 @@
+package ports
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// SortKey is the sort order of a listing (table column in the TUI, --sort in tapas list).
+type SortKey int
+
+const (
+	SortByPort SortKey = iota
+	SortByUptime
+	SortByProcess
+)
+
+func (k SortKey) String() string {
+	switch k {
+	case SortByPort:
+		return "Port"
+	case SortByUptime:
+		return "Uptime"
+	case SortByProcess:
+		return "Process"
+	default:
+		return "Port"
+	}
+}
+
+// ParseSortKey accepts "port", "uptime" or "process" (case-insensitive).
+func ParseSortKey(s string) (SortKey, error) {
+	for _, k := range []SortKey{SortByPort, SortByUptime, SortByProcess} {
+		if strings.EqualFold(s, k.String()) {
+			return k, nil
+		}
+	}
+	return SortByPort, fmt.Errorf("unknown sort key %q (use port, uptime or process)", s)
+}
+
+// FilterAndSort applies the search query (free text plus is:/cap:/ns: filter tokens) and sorts.
+// staleOnly keeps only stale listeners. This is what the TUI shows and tapas list prints.
+func FilterAndSort(list []Port, query string, sortKey SortKey, staleOnly bool) []Port {
+	var out []Port
+	q, terms := parseQuery(strings.ToLower(query))
+	for _, p := range list {
+		if staleOnly && !p.IsStale() {
+			continue
+		}
+		if !matchesTerms(p, terms) {
+			continue
+		}
+		if q == "" || portMatches(p, q) {
+			out = append(out, p)
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		return lessPort(out[i], out[j], sortKey)
+	})
+	return out
+}
+
+// filterTerm is a structured search token: is:stale, is:public, is:setuid, cap:net_bind_service, ns:net.
+type filterTerm struct {
+	key, value string
+}
+
+// filterKeys are the prefixes recognized as filter tokens; anything else is free-text search.
+var filterKeys = map[string]bool{"is": true, "cap": true, "ns": true}
+
+// parseQuery splits a lowercased query into free text and filter tokens (all tokens must match).
+func parseQuery(query string) (string, []filterTerm) {
+	var text []string
+	var terms []filterTerm
+	for _, field := range strings.Fields(query) {
+		if key, value, ok := strings.Cut(field, ":"); ok && filterKeys[key] {
+			terms = append(terms, filterTerm{key: key, value: value})
+			continue
+		}
+		text = append(text, field)
+	}
+	return strings.Join(text, " "), terms
+}
+
+func matchesTerms(p Port, terms []filterTerm) bool {
+	for _, t := range terms {
+		if !matchesTerm(p, t) {
+			return false
+		}
+	}
+	return true
+}
+
+func matchesTerm(p Port, t filterTerm) bool {
+	if t.key == "is" {
+		switch t.value {
+		case "stale":
+			return p.IsStale()
+		case "public":
+			return IsPublicBind(p.BindAddress)
+		case "docker":
+			return p.DockerContainerName != "" || p.InDocker
+		case "stopped":
+			return p.Stopped
+		case "system":
+			return IsSystemPort(p.PortNum) || IsSystemProcess(p.Process)
+		}
+	}
+	// Remaining terms (is:setuid, is:root, cap:..., ns:...) are security filters.
+	return p.Security != nil && p.Security.MatchesFilter(t.key, t.value)
+}
+
+func portMatches(p Port, q string) bool {
+	if strings.Contains(strings.ToLower(fmt.Sprint(p.PortNum)), q) {
+		return true
+	}
+	if strings.Contains(strings.ToLower(p.Process), q) {
+		return true
+	}
+	if strings.Contains(strings.ToLower(p.Project()), q) {
+		return true
+	}
+	if p.ProjectDisplayName != "" && strings.Contains(strings.ToLower(p.ProjectDisplayName), q) {
+		return true
+	}
+	if strings.Contains(strings.ToLower(p.WorkingDir), q) {
+		return true
+	}
+	if p.Framework != "" && strings.Contains(strings.ToLower(p.Framework), q) {
+		return true
+	}
+	if p.DockerContainerName != "" && strings.Contains(strings.ToLower(p.DockerContainerName), q) {
+		return true
+	}
+	if p.DockerImage != "" && strings.Contains(strings.ToLower(p.DockerImage), q) {
+		return true
+	}
+	if p.Environment != "" && strings.Contains(strings.ToLower(p.Environment), q) {
+		return true
+	}
+	// Search the redacted command so secrets cannot be probed by typing them.
+	if p.Command != "" && strings.Contains(strings.ToLower(p.SafeCommand()), q) {
+		return true
+	}
+	return false
+}
+
+func lessPort(a, b Port, sortKey SortKey) bool {
+	switch sortKey {
+	case SortByPort:
+		return a.PortNum < b.PortNum
+	case SortByUptime:
+		ua, ub := a.Uptime(), b.Uptime()
+		if ua != ub {
+			return ua > ub // descending: longest uptime first
+		}
+		return a.PortNum < b.PortNum
+	case SortByProcess:
+		pa, pb := strings.ToLower(a.Process), strings.ToLower(b.Process)
+		if pa != pb {
+			return pa < pb
+		}
+		return a.PortNum < b.PortNum
+	default:
+		return a.PortNum < b.PortNum
+	}
+}
@@ -0,0 +1,288 @@
+package ports
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// History events. TAPAS only sees listeners when it lists ports, so first-seen and last-seen bound
+// when a listener ran rather than pinpointing its start and exit.
+const (
+	HistoryFirstSeen = "first-seen"
+	HistoryLastSeen  = "last-seen"
+	HistoryPIDChange = "pid-change" // another PID of the same process took over the port (a restart)
+	HistoryPeak      = "peak"       // connections reached a new high (logged at 2, 4, 8, ...)
+)
+
+// Defaults for SetHistoryLog.
+const (
+	DefaultHistoryMaxBytes = 1 << 20
+	DefaultHistoryFiles    = 3
+)
+
+// HistoryEvent is one line of the history log.
+type HistoryEvent struct {
+	Time        time.Time  `json:"time"`
+	Event       string     `json:"event"`
+	Port        uint16     `json:"port"`
+	PID         int        `json:"pid"`
+	OldPID      int        `json:"old_pid,omitempty"` // pid-change
+	Process     string     `json:"process,omitempty"`
+	Project     string     `json:"project,omitempty"`
+	Command     string     `json:"command,omitempty"` // redacted; first-seen and pid-change
+	Started     *time.Time `json:"started,omitempty"` // process start time, when known
+	FirstSeen   *time.Time `json:"first_seen,omitempty"`
+	Connections int        `json:"connections,omitempty"` // peak: the new high; last-seen and pid-change: the old listener's peak
+}
+
+var (
+	historyMu       sync.Mutex
+	historyPath     string
+	historyMaxBytes int64 = DefaultHistoryMaxBytes
+	historyFiles          = DefaultHistoryFiles
+)
+
+// SetHistoryLog sets the JSON-lines file listener events are appended to; empty disables history.
+// The file is rotated to path.1, path.2, ... when it would exceed maxBytes, keeping files rotated
+// copies. Zero values use the defaults.
+func SetHistoryLog(path string, maxBytes int64, files int) {
+	historyMu.Lock()
+	defer historyMu.Unlock()
+	historyPath = path
+	historyMaxBytes = maxBytes
+	if maxBytes <= 0 {
+		historyMaxBytes = DefaultHistoryMaxBytes
+	}
+	historyFiles = files
+	if files <= 0 {
+		historyFiles = DefaultHistoryFiles
+	}
+}
+
+// HistoryLogPath returns the configured history log ("" when disabled).
+func HistoryLogPath() string {
+	historyMu.Lock()
+	defer historyMu.Unlock()
+	return historyPath
+}
+
+// recordHistory appends events to the log. Best effort: history must not fail a listing.
+func recordHistory(events []HistoryEvent) {
+	historyMu.Lock()
+	defer historyMu.Unlock()
+	if historyPath == "" || len(events) == 0 {
+		return
+	}
+	var buf bytes.Buffer
+	for _, e := range events {
+		data, err := json.Marshal(e)
+		if err != nil {
+			continue
+		}
+		buf.Write(append(data, '\n'))
+	}
+	if info, err := os.Stat(historyPath); err == nil && info.Size()+int64(buf.Len()) > historyMaxBytes {
+		rotateHistory()
+	}
+	if err := os.MkdirAll(filepath.Dir(historyPath), 0o700); err != nil {
+		return
+	}
+	f, err := os.OpenFile(historyPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return
+	}
+	_, _ = f.Write(buf.Bytes())
+	f.Close()
+}
+
+// rotateHistory shifts path.N-1 to path.N (dropping the oldest) and path to path.1. Caller holds historyMu.
+func rotateHistory() {
+	os.Remove(fmt.Sprintf("%s.%d", historyPath, historyFiles))
+	for i := historyFiles - 1; i >= 1; i-- {
+		os.Rename(fmt.Sprintf("%s.%d", historyPath, i), fmt.Sprintf("%s.%d", historyPath, i+1))
+	}
+	os.Rename(historyPath, historyPath+".1")
+}
+
+// ReadHistory returns every event in the log and its rotated files, oldest first.
+// Unreadable lines are skipped. Returns nil when history is disabled.
+func ReadHistory() ([]HistoryEvent, error) {
+	historyMu.Lock()
+	path, files := historyPath, historyFiles
+	historyMu.Unlock()
+	if path == "" {
+		return nil, nil
+	}
+	var events []HistoryEvent
+	for i := files; i >= 0; i-- {
+		name := path
+		if i > 0 {
+			name = fmt.Sprintf("%s.%d", path, i)
+		}
+		f, err := os.Open(name)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		sc := bufio.NewScanner(f)
+		sc.Buffer(make([]byte, 64*1024), 1024*1024)
+		for sc.Scan() {
+			var e HistoryEvent
+			if json.Unmarshal(sc.Bytes(), &e) == nil {
+				events = append(events, e)
+			}
+		}
+		err = sc.Err()
+		f.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+	sort.SliceStable(events, func(i, j int) bool { return events[i].Time.Before(events[j].Time) })
+	return events, nil
+}
+
+// Details summarises the event for tapas history and the UI's details view.
+func (e HistoryEvent) Details() string {
+	var parts []string
+	switch e.Event {
+	case HistoryFirstSeen, HistoryPIDChange:
+		if e.OldPID > 0 {
+			parts = append(parts, fmt.Sprintf("was PID %d", e.OldPID))
+		}
+		if e.Started != nil {
+			parts = append(parts, "started "+e.Started.Local().Format("2006-01-02 15:04"))
+		}
+		if e.Command != "" {
+			parts = append(parts, e.Command)
+		}
+	case HistoryLastSeen:
+		if e.FirstSeen != nil && e.Time.After(*e.FirstSeen) {
+			parts = append(parts, "seen for "+FormatUptime(e.Time.Sub(*e.FirstSeen)))
+		}
+		if e.Connections > 0 {
+			parts = append(parts, fmt.Sprintf("peak %d connections", e.Connections))
+		}
+	case HistoryPeak:
+		parts = append(parts, fmt.Sprintf("%d connections", e.Connections))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// HistoryQuery selects events: by port, by project or process name (case-insensitive), and by time.
+type HistoryQuery struct {
+	Port  uint16
+	Name  string
+	Since time.Time
+}
+
+// Match reports whether e is selected by q.
+func (q HistoryQuery) Match(e HistoryEvent) bool {
+	if q.Port != 0 && e.Port != q.Port {
+		return false
+	}
+	if q.Name != "" && !strings.EqualFold(e.Project, q.Name) && !strings.EqualFold(e.Process, q.Name) {
+		return false
+	}
+	return !e.Time.Before(q.Since)
+}
+
+// ListenersAt replays events (oldest first) and returns the latest first-seen or pid-change event
+// of every listener that was running at t, by port.
+func ListenersAt(events []HistoryEvent, t time.Time) []HistoryEvent {
+	alive := make(map[string]HistoryEvent)
+	key := func(port uint16, pid int) string { return fmt.Sprintf("%d/%d", port, pid) }
+	for _, e := range events {
+		if e.Time.After(t) {
+			break
+		}
+		switch e.Event {
+		case HistoryFirstSeen:
+			alive[key(e.Port, e.PID)] = e
+		case HistoryPIDChange:
+			delete(alive, key(e.Port, e.OldPID))
+			alive[key(e.Port, e.PID)] = e
+		case HistoryLastSeen:
+			delete(alive, key(e.Port, e.PID))
+		}
+	}
+	out := make([]HistoryEvent, 0, len(alive))
+	for _, e := range alive {
+		out = append(out, e)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Port != out[j].Port {
+			return out[i].Port < out[j].Port
+		}
+		return out[i].PID < out[j].PID
+	})
+	return out
+}
+
+// historyEvents compares the previous and current activity (see ActivityStore.Observe) and returns
+// what changed. A listener that vanished while the same process name appeared on its port under a
+// new PID is reported as one pid-change. Peaks are marked as logged in current.
+func historyEvents(previous, current ActivityIndex, list []Port, now time.Time) []HistoryEvent {
+	byKey := make(map[string]Port, len(list))
+	for _, p := range list {
+		byKey[activityKey(p)] = p
+	}
+	gone := make(map[uint16][]Activity)
+	for key, a := range previous {
+		if _, ok := current[key]; !ok {
+			gone[a.Port] = append(gone[a.Port], a)
+		}
+	}
+	var events []HistoryEvent
+	keys := make([]string, 0, len(current))
+	for key := range current {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		a := current[key]
+		if _, ok := previous[key]; !ok {
+			p := byKey[key]
+			e := HistoryEvent{Time: now, Event: HistoryFirstSeen, Port: a.Port, PID: a.PID, Process: a.Process,
+				Project: a.Project, Command: strings.TrimSpace(p.SafeCommand())}
+			if !p.StartTime.IsZero() {
+				started := p.StartTime
+				e.Started = &started
+			}
+			for i, old := range gone[a.Port] {
+				if old.Process == a.Process && old.PID != a.PID {
+					e.Event, e.OldPID, e.Connections = HistoryPIDChange, old.PID, old.Peak
+					gone[a.Port] = append(gone[a.Port][:i:i], gone[a.Port][i+1:]...)
+					break
+				}
+			}
+			events = append(events, e)
+		}
+		if a.Peak >= 2 && a.Peak >= 2*a.PeakLogged {
+			events = append(events, HistoryEvent{Time: now, Event: HistoryPeak, Port: a.Port, PID: a.PID,
+				Process: a.Process, Project: a.Project, Connections: a.Peak})
+			a.PeakLogged = a.Peak
+			current[key] = a
+		}
+	}
+	for _, list := range gone {
+		for _, old := range list {
+			first := old.FirstSeen
+			events = append(events, HistoryEvent{Time: old.LastSeen, Event: HistoryLastSeen, Port: old.Port, PID: old.PID,
+				Process: old.Process, Project: old.Project, FirstSeen: &first, Connections: old.Peak})
+		}
+	}
+	sort.SliceStable(events, func(i, j int) bool { return events[i].Time.Before(events[j].Time) })
+	return events
+}
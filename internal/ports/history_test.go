@@ -0,0 +1,79 @@
+package ports
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestHistoryEvents(t *testing.T) {
+	t0 := time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC)
+	redis := Port{PID: 100, PortNum: 6379, Process: "redis-server", ConnectionCount: 3}
+	next := Port{PID: 200, PortNum: 3000, Process: "node"}
+	previous := ActivityIndex{
+		activityKey(Port{PID: 90, PortNum: 6379}): {PID: 90, Port: 6379, Process: "redis-server", FirstSeen: t0, LastSeen: t0, Peak: 5},
+		activityKey(Port{PID: 80, PortNum: 8080}): {PID: 80, Port: 8080, Process: "java", FirstSeen: t0, LastSeen: t0},
+	}
+	now := t0.Add(time.Hour)
+	current := ActivityIndex{
+		activityKey(redis): {PID: 100, Port: 6379, Process: "redis-server", FirstSeen: now, LastSeen: now, Peak: 3},
+		activityKey(next):  {PID: 200, Port: 3000, Process: "node", FirstSeen: now, LastSeen: now},
+	}
+	events := historyEvents(previous, current, []Port{redis, next}, now)
+	got := make(map[string]HistoryEvent)
+	for _, e := range events {
+		got[e.Event+"/"+e.Process] = e
+	}
+	if len(events) != 4 {
+		t.Fatalf("got %d events, want 4: %+v", len(events), events)
+	}
+	if e := got[HistoryPIDChange+"/redis-server"]; e.OldPID != 90 || e.PID != 100 || e.Connections != 5 {
+		t.Errorf("pid-change: %+v", e)
+	}
+	if e := got[HistoryFirstSeen+"/node"]; e.Port != 3000 {
+		t.Errorf("first-seen: %+v", e)
+	}
+	if e := got[HistoryLastSeen+"/java"]; !e.Time.Equal(t0) || e.Port != 8080 {
+		t.Errorf("last-seen should carry the last observation time: %+v", e)
+	}
+	if e := got[HistoryPeak+"/redis-server"]; e.Connections != 3 || current[activityKey(redis)].PeakLogged != 3 {
+		t.Errorf("peak: %+v", e)
+	}
+
+	at := ListenersAt(events, now)
+	if len(at) != 2 || at[0].Port != 3000 || at[1].PID != 100 {
+		t.Errorf("ListenersAt: %+v", at)
+	}
+}
+
+func TestHistoryRotation(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.jsonl")
+	SetHistoryLog(path, 300, 2)
+	defer SetHistoryLog("", 0, 0)
+	t0 := time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC)
+	for i := 0; i < 20; i++ {
+		recordHistory([]HistoryEvent{{Time: t0.Add(time.Duration(i) * time.Minute), Event: HistoryFirstSeen, Port: uint16(3000 + i), PID: i + 1}})
+	}
+	if _, err := os.Stat(path + ".3"); err == nil {
+		t.Error("kept more rotated files than configured")
+	}
+	for _, name := range []string{path, path + ".1", path + ".2"} {
+		info, err := os.Stat(name)
+		if err != nil || info.Size() > 300 {
+			t.Errorf("%s: %v, size over the limit", name, err)
+		}
+	}
+	events, err := ReadHistory()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) == 0 || events[len(events)-1].Port != 3019 {
+		t.Fatalf("newest event missing: %+v", events)
+	}
+	for i := 1; i < len(events); i++ {
+		if events[i].Time.Before(events[i-1].Time) {
+			t.Fatal("events not oldest first")
+		}
+	}
+}
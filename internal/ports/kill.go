@@ -1,64 +1,131 @@
 package ports
 
 import (
+	"errors"
 	"fmt"
 	"strings"
 	"syscall"
+	"time"
 )
 
 // KillResult is the result of a kill attempt.
 type KillResult struct {
 	OK    bool
 	Error string
+	// NeedsPrivilege is set when the signal failed with EPERM (another user's process);
+	// front-ends can offer to retry through the privileged helper (ElevatedSignalCmd).
+	NeedsPrivilege bool
 }
 
-// Kill sends SIGTERM to the process identified by pid.
-// Uses syscall.Kill directly so it works reliably on macOS (os.FindProcess+Signal can fail there).
-// Caller is responsible for confirmation; this package does not prompt.
-func Kill(pid int) KillResult {
-	if pid <= 0 {
-		return KillResult{OK: false, Error: "invalid pid"}
-	}
-	err := syscall.Kill(pid, syscall.SIGTERM)
+// killError converts a signal error into a failed KillResult.
+func killError(err error) KillResult {
+	return KillResult{OK: false, Error: signalErrorMessage(err), NeedsPrivilege: errors.Is(err, syscall.EPERM)}
+}
+
+// errIdentityChanged means the PID now belongs to a different process than the Port snapshot.
+var errIdentityChanged = errors.New("process changed since it was listed (PID reused); refresh and try again")
+
+// startTimeTolerance absorbs rounding in start times derived from /proc uptime or ps lstart.
+const startTimeTolerance = time.Second
+
+// verifyIdentity checks that pid still belongs to the process in the snapshot:
+// same start time and, when known, the same executable. Fields unknown in the snapshot are not checked.
+func verifyIdentity(p Port) error {
+	start, exe, err := processIdentity(p.PID)
 	if err != nil {
-		msg := err.Error()
-		if strings.Contains(strings.ToLower(msg), "permission") || strings.Contains(strings.ToLower(msg), "operation not permitted") {
-			msg = "permission denied (try running TAPAS with sudo to kill system processes)"
-		}
-		if strings.Contains(strings.ToLower(msg), "no such process") || strings.Contains(strings.ToLower(msg), "esrch") {
-			msg = "process already exited"
+		return syscall.ESRCH
+	}
+	if !p.StartTime.IsZero() && !start.IsZero() {
+		d := start.Sub(p.StartTime)
+		if d < -startTimeTolerance || d > startTimeTolerance {
+			return errIdentityChanged
 		}
-		return KillResult{OK: false, Error: msg}
+	}
+	if p.Executable != "" && exe != "" && exe != p.Executable {
+		return errIdentityChanged
+	}
+	return nil
+}
+
+// Signal sends sig to the process in the Port snapshot after verifying the PID was not reused.
+// On Linux the check and the signal go through a pidfd, so there is no window for reuse in between.
+// Deny verdicts of the installed policy are refused here; typed confirmation for protected
+// listeners is the caller's job, as is any other confirmation (this package does not prompt).
+func Signal(p Port, sig syscall.Signal) KillResult {
+	if p.PID <= 0 {
+		return KillResult{OK: false, Error: "invalid pid"}
+	}
+	if err := sendSignal(p, sig); err != nil {
+		return killError(err)
 	}
 	return KillResult{OK: true}
 }
 
-// KillPort kills the process listening on the given port by PID (sends SIGTERM).
-// port is only used for error messages; the actual target is pid.
-func KillPort(port uint16, pid int) KillResult {
-	r := Kill(pid)
+// sendSignal is the single path every signal takes: policy check, verified delivery, audit.
+func sendSignal(p Port, sig syscall.Signal) error {
+	err := enforcePolicy(p)
+	if err == nil {
+		err = signalVerified(p, sig)
+	}
+	auditSignal(p, sig, err)
+	return err
+}
+
+// signalErrorMessage turns a signal error into the short text shown to the user.
+func signalErrorMessage(err error) string {
+	var denied *ErrPolicyDenied
+	if errors.Is(err, errIdentityChanged) || errors.As(err, &denied) {
+		return err.Error()
+	}
+	msg := err.Error()
+	lower := strings.ToLower(msg)
+	if strings.Contains(lower, "permission") || strings.Contains(lower, "operation not permitted") {
+		msg = "permission denied (process belongs to another user)"
+	}
+	if strings.Contains(lower, "no such process") || strings.Contains(lower, "esrch") {
+		msg = "process already exited"
+	}
+	return msg
+}
+
+// Kill sends SIGTERM to the process in the Port snapshot (see Signal).
+func Kill(p Port) KillResult {
+	return Signal(p, syscall.SIGTERM)
+}
+
+// KillPort kills the process listening on p.PortNum (sends SIGTERM).
+// The port is only used for error messages; the target is the verified PID.
+func KillPort(p Port) KillResult {
+	r := Kill(p)
 	if !r.OK && r.Error != "" {
-		r.Error = fmt.Sprintf("Failed to kill port %d (%s)", port, r.Error)
+		r.Error = fmt.Sprintf("Failed to kill port %d (%s)", p.PortNum, r.Error)
 	}
 	return r
 }
 
 // KillPortForce kills the process with SIGKILL (cannot be ignored by the process).
 // Use when SIGTERM does nothing (e.g. some GUI apps like Adobe).
-func KillPortForce(port uint16, pid int) KillResult {
-	if pid <= 0 {
-		return KillResult{OK: false, Error: fmt.Sprintf("Failed to kill port %d (invalid pid)", port)}
+func KillPortForce(p Port) KillResult {
+	r := Signal(p, syscall.SIGKILL)
+	if !r.OK && r.Error != "" {
+		r.Error = fmt.Sprintf("Failed to kill port %d (%s)", p.PortNum, r.Error)
 	}
-	err := syscall.Kill(pid, syscall.SIGKILL)
-	if err != nil {
-		msg := err.Error()
-		if strings.Contains(strings.ToLower(msg), "permission") || strings.Contains(strings.ToLower(msg), "operation not permitted") {
-			msg = "permission denied (try running TAPAS with sudo)"
+	return r
+}
+
+// exitPollInterval is how often pollExit checks a process that cannot be waited on directly.
+const exitPollInterval = 50 * time.Millisecond
+
+// pollExit polls until the snapshot's process is gone or timeout elapses.
+func pollExit(p Port, timeout time.Duration) bool {
+	deadline := time.Now().Add(timeout)
+	for {
+		if verifyIdentity(p) != nil {
+			return true
 		}
-		if strings.Contains(strings.ToLower(msg), "no such process") || strings.Contains(strings.ToLower(msg), "esrch") {
-			msg = "process already exited"
+		if time.Now().After(deadline) {
+			return false
 		}
-		return KillResult{OK: false, Error: fmt.Sprintf("Failed to kill port %d (%s)", port, msg)}
+		time.Sleep(exitPollInterval)
 	}
-	return KillResult{OK: true}
 }
@@ -0,0 +1,66 @@
+package ports
+
+import (
+	"os/exec"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestSignal_RefusesReusedPID(t *testing.T) {
+	cmd := exec.Command("sleep", "30")
+	if err := cmd.Start(); err != nil {
+		t.Skip("sleep not available:", err)
+	}
+	defer func() {
+		_ = cmd.Process.Kill()
+		_ = cmd.Wait()
+	}()
+	start, exe, err := processIdentity(cmd.Process.Pid)
+	if err != nil {
+		t.Skip("process identity unavailable:", err)
+	}
+
+	stale := Port{PID: cmd.Process.Pid, StartTime: start.Add(-time.Hour), Executable: exe}
+	if r := Signal(stale, syscall.SIGTERM); r.OK || !strings.Contains(r.Error, "PID reused") {
+		t.Fatalf("Signal with stale snapshot = %+v, want PID reuse error", r)
+	}
+
+	current := Port{PID: cmd.Process.Pid, StartTime: start, Executable: exe}
+	if r := Signal(current, syscall.SIGTERM); !r.OK {
+		t.Fatalf("Signal = %+v, want OK", r)
+	}
+	if !WaitExit(current, 5*time.Second) {
+		t.Error("WaitExit = false, want process to exit after SIGTERM")
+	}
+}
+
+func TestParseSignal(t *testing.T) {
+	tests := []struct {
+		in   string
+		want syscall.Signal
+	}{
+		{"HUP", syscall.SIGHUP},
+		{"sigusr2", syscall.SIGUSR2},
+		{"SIGSTOP", syscall.SIGSTOP},
+		{"9", syscall.SIGKILL},
+	}
+	for _, tt := range tests {
+		got, err := ParseSignal(tt.in)
+		if err != nil || got != tt.want {
+			t.Errorf("ParseSignal(%q) = %v, %v; want %v", tt.in, got, err, tt.want)
+		}
+	}
+	if _, err := ParseSignal("BOGUS"); err == nil {
+		t.Error("ParseSignal(BOGUS) should fail")
+	}
+}
+
+func TestTerminateSummaryNamesSignal(t *testing.T) {
+	r := TerminateResult{KillResult: KillResult{OK: true}, Signal: syscall.SIGINT, Processes: 1, Exited: true,
+		Escalated: true, Ports: []uint16{3000}, PortFree: true, Elapsed: 1500 * time.Millisecond}
+	if got := r.Summary(); !strings.HasPrefix(got, "ignored SIGINT; SIGKILL sent") {
+		t.Errorf("Summary = %q, want it to name SIGINT", got)
+	}
+}
@@ -0,0 +1,131 @@
+package ports
+
+import (
+	"fmt"
+	"time"
+)
+
+// Display labels shared by the TUI and tapas list, so both describe a listener the same way.
+
+// IsPublicBind reports whether the bind address exposes the port publicly (all interfaces).
+// IPv4: 0.0.0.0, *; IPv6: ::, [::].
+func IsPublicBind(addr string) bool {
+	switch addr {
+	case "0.0.0.0", "*", "", "::", "[::]":
+		return true
+	default:
+		return false
+	}
+}
+
+// BindLabel returns LOCAL, PUBLIC, or the bind address for security awareness.
+func BindLabel(addr string) string {
+	switch addr {
+	case "127.0.0.1", "::1":
+		return "LOCAL"
+	case "0.0.0.0", "*", "", "::", "[::]":
+		return "PUBLIC"
+	default:
+		return addr
+	}
+}
+
+// ProcessLabel returns the process column text: Docker, "PostgreSQL (local)", "Framework (process)", or process name.
+func ProcessLabel(p *Port) string {
+	if p.DockerContainerName != "" {
+		s := "Docker → " + p.DockerContainerName
+		if p.DockerImage != "" {
+			s += " (" + p.DockerImage + ")"
+		}
+		return s
+	}
+	if db := DatabaseProductName(p.PortNum); db != "" {
+		return db + " (" + BindLabel(p.BindAddress) + ")"
+	}
+	if p.Framework != "" {
+		if p.Process != "" && p.Process != "—" {
+			return p.Framework + " (" + p.Process + ")"
+		}
+		return p.Framework
+	}
+	if p.Process == "" {
+		return "—"
+	}
+	return p.Process
+}
+
+// AppBadge returns the framework badge + Docker indicator + common app name for the APP column.
+func AppBadge(p *Port) string {
+	var badge string
+	if p.DockerContainerName != "" {
+		badge = "Docker"
+	} else if p.Framework != "" {
+		badge = p.Framework
+		if p.InDocker {
+			badge += " D"
+		}
+	} else if app := AppName(p.PortNum, p.Process); app != "" {
+		badge = app
+		if p.InDocker {
+			badge += " D"
+		}
+	} else {
+		badge = "—"
+		if p.InDocker {
+			badge += " D"
+		}
+	}
+	return badge
+}
+
+// ProjectLabel returns the PROJECT column text: "dili (Next.js)" or for Docker "pulso-api (Docker)".
+func ProjectLabel(p *Port) string {
+	base := ""
+	if p.DockerContainerName != "" {
+		// Docker: use container name as project context (host cwd is meaningless)
+		base = p.DockerContainerName
+	} else {
+		base = p.ProjectDisplayName
+		if base == "" {
+			base = p.Project()
+		}
+	}
+	if base == "" || base == "/" {
+		base = "—"
+	}
+	if base == "—" {
+		return base
+	}
+	if p.DockerContainerName != "" {
+		return base + " (Docker)"
+	}
+	if p.Framework != "" {
+		return base + " (" + p.Framework + ")"
+	}
+	return base
+}
+
+// FormatUptime renders a duration as 42s, 5m, 3h or 2d ("—" when unknown).
+func FormatUptime(d time.Duration) string {
+	if d <= 0 {
+		return "—"
+	}
+	if d < time.Minute {
+		return fmt.Sprintf("%ds", int(d.Seconds()))
+	}
+	if d < time.Hour {
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	}
+	if d < 24*time.Hour {
+		return fmt.Sprintf("%dh", int(d.Hours()))
+	}
+	return fmt.Sprintf("%dd", int(d.Hours()/24))
+}
+
+// OrDash returns s, or "-" when it is empty.
+func OrDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
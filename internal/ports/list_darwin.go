@@ -4,6 +4,7 @@ package ports
 
 import (
 	"bufio"
+	"os"
 	"os/exec"
 	"strconv"
 	"strings"
@@ -63,11 +64,12 @@ func parseLsof(out []byte) ([]Port, error) {
 		workingDir := getWorkingDir(pid)
 		command := getCommand(pid)
 		process := fields[0]
+		ppid, stopped := procState(pid)
 		bindAddr := addr
 		if bindAddr == "*" {
 			bindAddr = "0.0.0.0"
 		}
-		list = append(list, Port{
+		p := Port{
 			PortNum:            uint16(port),
 			PID:                pid,
 			Process:            process,
@@ -80,7 +82,12 @@ func parseLsof(out []byte) ([]Port, error) {
 			BindAddress:        bindAddr,
 			ProjectDisplayName: ProjectDisplayName(workingDir),
 			Environment:        DetectEnvironment(command),
-		})
+			PPID:               ppid,
+			Stopped:            stopped,
+		}
+		// cwd/exe deletion is not observable via lsof/ps; only orphaned detection applies on macOS.
+		DetectStale(&p, false, false)
+		list = append(list, p)
 	}
 	return list, sc.Err()
 }
@@ -118,12 +125,12 @@ func processStartTime(pid int) (time.Time, error) {
 	if err != nil {
 		return time.Time{}, err
 	}
-	s := strings.TrimSpace(string(out))
+	// "Mon Jan  2 15:04:05 2006": collapse the day padding so the layout below matches.
+	s := strings.Join(strings.Fields(string(out)), " ")
 	if s == "" {
 		return time.Time{}, nil
 	}
-	// "Mon Jan  2 15:04:05 2006"
-	t, err := time.Parse("Mon Jan 2 15:04:05 2006", s)
+	t, err := time.ParseInLocation("Mon Jan 2 15:04:05 2006", s, time.Local)
 	if err != nil {
 		return time.Time{}, err
 	}
@@ -147,6 +154,35 @@ func getWorkingDir(pid int) string {
 	return ""
 }
 
+// processIdentity returns the current start time of pid, used to detect PID reuse.
+// The executable is not compared on macOS (Port.Executable is not collected there).
+func processIdentity(pid int) (time.Time, string, error) {
+	start, err := processStartTime(pid)
+	if err != nil {
+		return time.Time{}, "", err
+	}
+	if start.IsZero() {
+		return time.Time{}, "", os.ErrNotExist
+	}
+	return start, "", nil
+}
+
+// procState returns the parent PID and whether the process is stopped (ps state starting with T).
+func procState(pid int) (ppid int, stopped bool) {
+	cmd := exec.Command("ps", "-o", "ppid=,state=", "-p", strconv.Itoa(pid))
+	cmd.Env = []string{"LC_ALL=C"}
+	out, err := cmd.Output()
+	if err != nil {
+		return 0, false
+	}
+	fields := strings.Fields(string(out))
+	if len(fields) < 2 {
+		return 0, false
+	}
+	ppid, _ = strconv.Atoi(fields[0])
+	return ppid, strings.HasPrefix(fields[1], "T")
+}
+
 func getCommand(pid int) string {
 	cmd := exec.Command("ps", "-o", "command=", "-p", strconv.Itoa(pid))
 	cmd.Env = []string{"LC_ALL=C"}
@@ -156,3 +192,18 @@ func getCommand(pid int) string {
 	}
 	return strings.TrimSpace(string(out))
 }
+
+// PortListening reports whether any TCP socket is listening on port (lsof -t; cheap, no enrichment).
+func PortListening(port uint16) (bool, error) {
+	cmd := exec.Command("lsof", "-nP", "-iTCP:"+strconv.Itoa(int(port)), "-sTCP:LISTEN", "-t")
+	cmd.Env = []string{"LC_ALL=C"}
+	out, err := cmd.Output()
+	if err != nil {
+		// lsof exits 1 when nothing matches
+		if exitErr, ok := err.(*exec.ExitError); ok && exitErr.ExitCode() == 1 {
+			return false, nil
+		}
+		return false, err
+	}
+	return strings.TrimSpace(string(out)) != "", nil
+}
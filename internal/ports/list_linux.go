@@ -38,6 +38,7 @@ func (l *linuxLister) List() ([]Port, error) {
 // LISTEN 0      128    *:3000             *:*    users:(("node",pid=123,fd=20))
 func parseSS(out []byte) ([]Port, error) {
 	var list []Port
+	hostNS := hostNamespaces()
 	sc := bufio.NewScanner(strings.NewReader(string(out)))
 	sc.Scan() // header
 	for sc.Scan() {
@@ -56,26 +57,36 @@ func parseSS(out []byte) ([]Port, error) {
 		}
 		pid, process := pidAndProcessFromSS(line)
 		startTime, _ := processStartTimeLinux(pid)
-		workingDir := getWorkingDirLinux(pid)
+		workingDir, cwdDeleted := trimDeleted(getWorkingDirLinux(pid))
+		executable, exeDeleted := trimDeleted(getExecutableLinux(pid))
 		command := getCommandLinux(pid)
+		ppid, stopped := procStateLinux(pid)
 		bindAddr := bindFromSSAddr(addrStr)
 		if bindAddr == "*" {
 			bindAddr = "0.0.0.0"
 		}
-		list = append(list, Port{
-			PortNum:           uint16(port),
-			PID:               pid,
-			Process:           process,
-			Protocol:          "tcp",
-			StartTime:         startTime,
-			WorkingDir:        workingDir,
-			Command:           command,
-			Framework:         DetectFramework(workingDir, command, process),
-			InDocker:          isDocker(pid),
+		p := Port{
+			PortNum:            uint16(port),
+			PID:                pid,
+			Process:            process,
+			Protocol:           "tcp",
+			StartTime:          startTime,
+			WorkingDir:         workingDir,
+			Command:            command,
+			Framework:          DetectFramework(workingDir, command, process),
+			InDocker:           isDocker(pid),
 			BindAddress:        bindAddr,
 			ProjectDisplayName: ProjectDisplayName(workingDir),
 			Environment:        DetectEnvironment(command),
-		})
+			PPID:               ppid,
+			Stopped:            stopped,
+			Executable:         executable,
+			Args:               processArgv(pid),
+			Security:           readSecurityContext(pid, executable, hostNS),
+			SystemdUnit:        systemdUnit(pid),
+		}
+		DetectStale(&p, cwdDeleted, exeDeleted)
+		list = append(list, p)
 	}
 	return list, sc.Err()
 }
@@ -127,24 +138,11 @@ func processStartTimeLinux(pid int) (time.Time, error) {
 	if pid <= 0 {
 		return time.Time{}, nil
 	}
-	data, err := readFile("/proc/" + strconv.Itoa(pid) + "/stat")
+	fields, err := procStatFields(pid)
 	if err != nil {
 		return time.Time{}, err
 	}
-	// Field 22 is starttime (jiffies since boot)
-	start := 0
-	for n := 0; n < 21; n++ {
-		i := strings.Index(data[start:], " ")
-		if i < 0 {
-			return time.Time{}, nil
-		}
-		start += i + 1
-	}
-	end := start
-	for end < len(data) && data[end] != ' ' {
-		end++
-	}
-	jiffies, err := strconv.ParseUint(data[start:end], 10, 64)
+	jiffies, err := statStartTime(fields)
 	if err != nil {
 		return time.Time{}, err
 	}
@@ -182,6 +180,75 @@ func getWorkingDirLinux(pid int) string {
 	return path
 }
 
+func getExecutableLinux(pid int) string {
+	if pid <= 0 {
+		return ""
+	}
+	path, err := os.Readlink("/proc/" + strconv.Itoa(pid) + "/exe")
+	if err != nil {
+		return ""
+	}
+	return path
+}
+
+// procStatFields returns the fields of /proc/<pid>/stat that follow "(comm)", so index 0 is
+// state (field 3 in proc(5)), 1 is ppid, 2 is pgrp, and so on. comm may contain spaces and
+// parentheses, so we split after the last ')'.
+func procStatFields(pid int) ([]string, error) {
+	data, err := readFile("/proc/" + strconv.Itoa(pid) + "/stat")
+	if err != nil {
+		return nil, err
+	}
+	return parseStatFields(data)
+}
+
+func parseStatFields(data string) ([]string, error) {
+	i := strings.LastIndex(data, ")")
+	if i < 0 {
+		return nil, os.ErrInvalid
+	}
+	return strings.Fields(data[i+1:]), nil
+}
+
+// statStartTime returns starttime (field 22 in proc(5), jiffies since boot) from procStatFields.
+func statStartTime(fields []string) (uint64, error) {
+	if len(fields) < 20 {
+		return 0, os.ErrInvalid
+	}
+	return strconv.ParseUint(fields[19], 10, 64)
+}
+
+// procStateLinux returns the parent PID and whether the process is stopped (state T).
+func procStateLinux(pid int) (ppid int, stopped bool) {
+	if pid <= 0 {
+		return 0, false
+	}
+	fields, err := procStatFields(pid)
+	if err != nil || len(fields) < 2 {
+		return 0, false
+	}
+	ppid, _ = strconv.Atoi(fields[1])
+	return ppid, fields[0] == "T"
+}
+
+// processIdentity returns the current start time and executable of pid, used to detect PID reuse.
+// Zombies count as exited: they no longer hold the port and cannot be signalled meaningfully.
+func processIdentity(pid int) (time.Time, string, error) {
+	fields, err := procStatFields(pid)
+	if err != nil {
+		return time.Time{}, "", err
+	}
+	if len(fields) > 0 && fields[0] == "Z" {
+		return time.Time{}, "", os.ErrNotExist
+	}
+	start, err := processStartTimeLinux(pid)
+	if err != nil {
+		return time.Time{}, "", err
+	}
+	exe, _ := trimDeleted(getExecutableLinux(pid))
+	return start, exe, nil
+}
+
 func getCommandLinux(pid int) string {
 	if pid <= 0 {
 		return ""
@@ -200,3 +267,19 @@ func readFile(path string) (string, error) {
 	}
 	return string(data), nil
 }
+
+// PortListening reports whether any TCP socket is listening on port (ss filter; cheap, no enrichment).
+func PortListening(port uint16) (bool, error) {
+	cmd := exec.Command("ss", "-tln", "sport = :"+strconv.Itoa(int(port)))
+	cmd.Env = []string{"LC_ALL=C"}
+	out, err := cmd.Output()
+	if err != nil {
+		return false, err
+	}
+	for _, line := range strings.Split(string(out), "\n") {
+		if strings.HasPrefix(line, "LISTEN") {
+			return true, nil
+		}
+	}
+	return false, nil
+}
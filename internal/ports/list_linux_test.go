@@ -0,0 +1,18 @@
+package ports
+
+import "testing"
+
+func TestStatStartTime(t *testing.T) {
+	// comm with spaces and a parenthesis, as set by npm run dev or a script under /tmp/my sleep.
+	for _, comm := range []string{"node", "npm run dev", "my sleep", "a) (b"} {
+		stat := "4242 (" + comm + ") S 1 4242 4242 0 -1 4194560 1532 0 0 0 12 3 0 0 20 0 11 0 987654 1234567 890 18446744073709551615\n"
+		fields, err := parseStatFields(stat)
+		if err != nil {
+			t.Fatalf("%q: %v", comm, err)
+		}
+		got, err := statStartTime(fields)
+		if err != nil || got != 987654 {
+			t.Errorf("%q: starttime = %d, %v; want 987654", comm, got, err)
+		}
+	}
+}
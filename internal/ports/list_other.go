@@ -2,7 +2,10 @@
 
 package ports
 
-import "errors"
+import (
+	"errors"
+	"time"
+)
 
 func init() {
 	defaultLister = &unsupportedLister{}
@@ -13,3 +16,11 @@ type unsupportedLister struct{}
 func (u *unsupportedLister) List() ([]Port, error) {
 	return nil, errors.New("TAPAS is supported on macOS and Linux only")
 }
+
+func processIdentity(pid int) (time.Time, string, error) {
+	return time.Time{}, "", errors.New("unsupported platform")
+}
+
+func PortListening(port uint16) (bool, error) {
+	return false, errors.New("TAPAS is supported on macOS and Linux only")
+}
@@ -0,0 +1,123 @@
+package ports
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// metricLabels are the labels of every listener series, in output order.
+var metricLabels = []string{"port", "protocol", "process", "project", "framework", "container", "bind"}
+
+// listenerMetric is one gauge family. Everything is a gauge (CPU time included) so the file parses
+// both as OpenMetrics and as the Prometheus text format read by node_exporter's textfile collector.
+type listenerMetric struct {
+	name, unit, help string
+	value            func(s metricSeries) (float64, bool) // false: not available, no sample
+}
+
+// metricSeries is one listener; IPv4 and IPv6 sockets with the same labels are merged.
+type metricSeries struct {
+	labels []string
+	port   Port
+	usage  *ProcessUsage
+}
+
+var listenerMetrics = []listenerMetric{
+	{"tapas_listener_up", "", "1 while the listener serves, 0 when its process is stopped (SIGSTOP).", func(s metricSeries) (float64, bool) {
+		return boolValue(!s.port.Stopped), true
+	}},
+	{"tapas_listener_connections", "", "Established connections to the port.", func(s metricSeries) (float64, bool) {
+		return float64(s.port.ConnectionCount), s.port.ConnectionCount >= 0
+	}},
+	{"tapas_listener_uptime_seconds", "seconds", "Time since the listening process started.", func(s metricSeries) (float64, bool) {
+		return s.port.Uptime().Seconds(), !s.port.StartTime.IsZero()
+	}},
+	{"tapas_listener_public", "", "1 when the port is bound to all interfaces.", func(s metricSeries) (float64, bool) {
+		return boolValue(IsPublicBind(s.port.BindAddress)), true
+	}},
+	{"tapas_listener_cpu_seconds", "seconds", "CPU time (user + system) used by the listening process.", func(s metricSeries) (float64, bool) {
+		if s.usage == nil {
+			return 0, false
+		}
+		return s.usage.CPUSeconds, true
+	}},
+	{"tapas_listener_resident_memory_bytes", "bytes", "Resident memory of the listening process.", func(s metricSeries) (float64, bool) {
+		if s.usage == nil {
+			return 0, false
+		}
+		return float64(s.usage.RSSBytes), true
+	}},
+}
+
+// WriteOpenMetrics writes list as gauges in the OpenMetrics text format, one series per listener
+// and metric. CPU and memory are included where the process is visible.
+func WriteOpenMetrics(w io.Writer, list []Port) error {
+	var pids []int
+	for _, p := range list {
+		if p.PID > 0 {
+			pids = append(pids, p.PID)
+		}
+	}
+	usage := processUsage(pids)
+	var series []metricSeries
+	index := make(map[string]int)
+	for _, p := range list {
+		labels := []string{strconv.Itoa(int(p.PortNum)), p.Protocol, p.Process, projectName(p), p.Framework, p.DockerContainerName, BindLabel(p.BindAddress)}
+		key := strings.Join(labels, "\x00")
+		if i, ok := index[key]; ok {
+			if p.ConnectionCount > series[i].port.ConnectionCount {
+				series[i].port.ConnectionCount = p.ConnectionCount
+			}
+			continue
+		}
+		s := metricSeries{labels: labels, port: p}
+		if u, ok := usage[p.PID]; ok {
+			s.usage = &u
+		}
+		index[key] = len(series)
+		series = append(series, s)
+	}
+	sort.SliceStable(series, func(i, j int) bool { return series[i].port.PortNum < series[j].port.PortNum })
+
+	bw := bufio.NewWriter(w)
+	for _, m := range listenerMetrics {
+		fmt.Fprintf(bw, "# TYPE %s gauge\n", m.name)
+		if m.unit != "" {
+			fmt.Fprintf(bw, "# UNIT %s %s\n", m.name, m.unit)
+		}
+		fmt.Fprintf(bw, "# HELP %s %s\n", m.name, m.help)
+		for _, s := range series {
+			v, ok := m.value(s)
+			if !ok {
+				continue
+			}
+			fmt.Fprintf(bw, "%s{%s} %s\n", m.name, formatLabels(s.labels), strconv.FormatFloat(v, 'f', -1, 64))
+		}
+	}
+	fmt.Fprintln(bw, "# EOF")
+	return bw.Flush()
+}
+
+func formatLabels(values []string) string {
+	parts := make([]string, len(metricLabels))
+	for i, name := range metricLabels {
+		parts[i] = fmt.Sprintf("%s=\"%s\"", name, escapeLabel(values[i]))
+	}
+	return strings.Join(parts, ",")
+}
+
+// escapeLabel escapes a label value: backslash, double quote and newline.
+func escapeLabel(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(s)
+}
+
+func boolValue(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,39 @@
+package ports
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestWriteOpenMetrics(t *testing.T) {
+	list := []Port{
+		{PortNum: 5432, Protocol: "tcp", Process: "postgres", BindAddress: "0.0.0.0", ConnectionCount: 2},
+		{PortNum: 5432, Protocol: "tcp", Process: "postgres", BindAddress: "::", ConnectionCount: 3}, // same series
+		{PortNum: 3000, Protocol: "tcp", Process: "node", ProjectDisplayName: `we"ird\name`, BindAddress: "127.0.0.1", Stopped: true},
+	}
+	var buf bytes.Buffer
+	if err := WriteOpenMetrics(&buf, list); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	for _, want := range []string{
+		`tapas_listener_connections{port="5432",protocol="tcp",process="postgres",project="",framework="",container="",bind="PUBLIC"} 3`,
+		`tapas_listener_up{port="3000",protocol="tcp",process="node",project="we\"ird\\name",framework="",container="",bind="LOCAL"} 0`,
+		`tapas_listener_public{port="5432",protocol="tcp",process="postgres",project="",framework="",container="",bind="PUBLIC"} 1`,
+		"# TYPE tapas_listener_resident_memory_bytes gauge\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("missing %s", want)
+		}
+	}
+	if n := strings.Count(out, "tapas_listener_up{"); n != 2 {
+		t.Errorf("%d up series, want 2 (IPv4 and IPv6 merged)", n)
+	}
+	if strings.Contains(out, "tapas_listener_uptime_seconds{") {
+		t.Error("uptime without a start time")
+	}
+	if !strings.HasSuffix(out, "# EOF\n") {
+		t.Error("missing # EOF")
+	}
+}
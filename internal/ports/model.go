@@ -19,6 +19,7 @@ type Port struct {
 	// Docker awareness: from docker ps port mapping (host port -> container)
 	DockerContainerName string // e.g. "my-api-container"
 	DockerImage         string // e.g. "postgres:15"
+	DockerContainerPort uint16 // port inside the container the host port is published to; 0 if unknown
 
 	// Bind address: what the port is listening on (127.0.0.1 = local, 0.0.0.0 = all interfaces)
 	BindAddress string // e.g. "127.0.0.1", "0.0.0.0", or specific IP
@@ -31,6 +32,25 @@ type Port struct {
 
 	// Environment: how the process was launched (npm, yarn, pnpm, poetry, pipenv, cargo, go).
 	Environment string
+
+	// Parent PID (0 if unknown) and resolved executable path (Linux: /proc/<pid>/exe).
+	PPID       int
+	Executable string
+
+	// Args is the exact argv (Linux: /proc/<pid>/cmdline). Nil when unknown; Command is the joined form.
+	Args []string
+
+	// Stopped is true when the process is paused (SIGSTOP / job control); it holds the port but does not serve.
+	Stopped bool
+
+	// Security is the process security context (capabilities, seccomp, namespaces, LSM label). Nil if unavailable.
+	Security *SecurityContext
+
+	// SystemdUnit is the service unit managing the process (Linux, e.g. "sshd.service"). Empty if none.
+	SystemdUnit string
+
+	// StaleReasons lists why the listener looks abandoned (orphaned, cwd deleted, exe replaced). Empty when healthy.
+	StaleReasons []StaleReason
 }
 
 // Uptime returns the duration since StartTime. If StartTime is zero, returns 0.
@@ -41,6 +61,11 @@ func (p *Port) Uptime() time.Duration {
 	return time.Since(p.StartTime)
 }
 
+// IsStale reports whether any stale condition was detected for this listener.
+func (p *Port) IsStale() bool {
+	return len(p.StaleReasons) > 0
+}
+
 // Project returns a short label for the project (working directory).
 // Returns the last element of the path or "—" if empty.
 func (p *Port) Project() string {
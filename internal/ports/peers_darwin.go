@@ -0,0 +1,73 @@
+//go:build darwin
+
+package ports
+
+import (
+	"bufio"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// connectedPeers lists established connections to port (lsof). Clients on this machine are named
+// when lsof can see their socket.
+func connectedPeers(port uint16) []Peer {
+	cmd := exec.Command("lsof", "-nP", "-iTCP:"+strconv.Itoa(int(port)), "-sTCP:ESTABLISHED", "-Fpcn")
+	cmd.Env = []string{"LC_ALL=C"}
+	out, err := cmd.Output()
+	if err != nil {
+		return nil
+	}
+	type client struct {
+		name string
+		pid  int
+	}
+	var serverSide []string
+	clients := make(map[string]client)
+	var cur client
+	sc := bufio.NewScanner(strings.NewReader(string(out)))
+	for sc.Scan() {
+		line := sc.Text()
+		if line == "" {
+			continue
+		}
+		switch line[0] {
+		case 'p':
+			cur = client{}
+			cur.pid, _ = strconv.Atoi(line[1:])
+		case 'c':
+			cur.name = line[1:]
+		case 'n': // 127.0.0.1:3000->127.0.0.1:52311
+			local, remote, ok := strings.Cut(line[1:], "->")
+			if !ok {
+				continue
+			}
+			if lp, ok := portFromAddr(local); ok && lp == port {
+				serverSide = append(serverSide, remote)
+			} else {
+				clients[local] = cur
+			}
+		}
+	}
+	peers := make([]Peer, 0, len(serverSide))
+	for _, addr := range serverSide {
+		c := clients[addr]
+		peers = append(peers, Peer{Address: addr, Process: c.name, PID: c.pid})
+	}
+	return peers
+}
+
+// portFromAddr parses the port of "127.0.0.1:3000" or "[::1]:3000".
+func portFromAddr(addr string) (uint16, bool) {
+	i := strings.LastIndex(addr, ":")
+	if i < 0 {
+		return 0, false
+	}
+	n, err := strconv.ParseUint(addr[i+1:], 10, 16)
+	return uint16(n), err == nil
+}
+
+// systemdUserUnit is always false: there is no systemd on macOS.
+func systemdUserUnit(pid int) bool {
+	return false
+}
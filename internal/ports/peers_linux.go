@@ -0,0 +1,63 @@
+//go:build linux
+
+package ports
+
+import (
+	"bufio"
+	"os"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// ssUsersRe extracts the first process from ss -p output: users:(("node",pid=4242,fd=21)).
+var ssUsersRe = regexp.MustCompile(`users:\(\("([^"]*)",pid=(\d+)`)
+
+// connectedPeers lists established connections to port. Clients on this machine are named when
+// their socket is visible to us (same user, or root).
+func connectedPeers(port uint16) []Peer {
+	p := strconv.Itoa(int(port))
+	cmd := exec.Command("ss", "-tnp", "state", "established", "( sport = :"+p+" or dport = :"+p+" )")
+	cmd.Env = []string{"LC_ALL=C"}
+	out, err := cmd.Output()
+	if err != nil {
+		return nil
+	}
+	type client struct {
+		name string
+		pid  int
+	}
+	var serverSide []string            // peer addresses of connections accepted on port
+	clients := make(map[string]client) // local address -> process, for connections made to port
+	sc := bufio.NewScanner(strings.NewReader(string(out)))
+	sc.Scan() // skip header
+	for sc.Scan() {
+		// With a state filter ss prints: Recv-Q Send-Q Local:Port Peer:Port [users:(...)]
+		fields := strings.Fields(sc.Text())
+		if len(fields) < 4 {
+			continue
+		}
+		local, peer := fields[2], fields[3]
+		if lp, ok := portFromSSAddr(local); ok && lp == int(port) {
+			serverSide = append(serverSide, peer)
+			continue
+		}
+		if m := ssUsersRe.FindStringSubmatch(sc.Text()); m != nil {
+			pid, _ := strconv.Atoi(m[2])
+			clients[local] = client{m[1], pid}
+		}
+	}
+	peers := make([]Peer, 0, len(serverSide))
+	for _, addr := range serverSide {
+		c := clients[addr]
+		peers = append(peers, Peer{Address: addr, Process: c.name, PID: c.pid})
+	}
+	return peers
+}
+
+// systemdUserUnit reports whether pid runs under the user's systemd instance (user@UID.service).
+func systemdUserUnit(pid int) bool {
+	data, err := os.ReadFile("/proc/" + strconv.Itoa(pid) + "/cgroup")
+	return err == nil && strings.Contains(string(data), "/user@")
+}
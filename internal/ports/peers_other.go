@@ -0,0 +1,11 @@
+//go:build !darwin && !linux
+
+package ports
+
+func connectedPeers(port uint16) []Peer {
+	return nil
+}
+
+func systemdUserUnit(pid int) bool {
+	return false
+}
@@ -0,0 +1,222 @@
+package ports
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// PolicyRules are patterns per attribute. Processes, images and units use glob syntax
+// (path.Match, case-insensitive); ports accept a number ("5432") or a range ("8000-8100").
+type PolicyRules struct {
+	Ports     []string
+	Processes []string
+	Images    []string
+	Units     []string
+}
+
+// Policy guards every kill path: denied listeners can never be signalled, protected ones
+// need typed confirmation, and ReadOnly removes destructive actions entirely.
+type Policy struct {
+	ReadOnly  bool
+	Protected PolicyRules
+	Deny      PolicyRules
+}
+
+// builtinDeny are listeners TAPAS never signals: killing them locks you out or breaks the OS.
+var builtinDeny = PolicyRules{
+	Processes: []string{"sshd", "systemd", "systemd-*", "launchd", "init"},
+}
+
+// Decision is the outcome of a policy check.
+type Decision int
+
+const (
+	Allow   Decision = iota
+	Confirm          // protected: the user must type the port number to proceed
+	Deny             // never signalled
+)
+
+// Verdict is a policy decision with a human-readable reason.
+type Verdict struct {
+	Decision Decision
+	Reason   string
+	Port     uint16 // the port the verdict is about: the one to type for Confirm
+}
+
+// ErrPolicyDenied reports a signal or container action refused by policy.
+type ErrPolicyDenied struct {
+	Reason string
+}
+
+func (e *ErrPolicyDenied) Error() string {
+	return "denied by policy: " + e.Reason
+}
+
+// Check evaluates the policy for p. Read-only beats everything, then deny rules, then protection.
+func (pol *Policy) Check(p Port) Verdict {
+	if pol.ReadOnly {
+		return Verdict{Decision: Deny, Reason: "read-only mode", Port: p.PortNum}
+	}
+	if reason := builtinDeny.match(p); reason != "" {
+		return Verdict{Decision: Deny, Reason: reason + " is always denied", Port: p.PortNum}
+	}
+	if reason := pol.Deny.match(p); reason != "" {
+		return Verdict{Decision: Deny, Reason: reason + " is always denied", Port: p.PortNum}
+	}
+	if reason := pol.Protected.match(p); reason != "" {
+		return Verdict{Decision: Confirm, Reason: reason + " is protected", Port: p.PortNum}
+	}
+	return Verdict{Decision: Allow, Port: p.PortNum}
+}
+
+// match returns a description of the first rule p matches ("port 5432", "process sshd"), or "".
+func (r PolicyRules) match(p Port) string {
+	if p.PortNum > 0 {
+		for _, spec := range r.Ports {
+			if portInSpec(p.PortNum, spec) {
+				return fmt.Sprintf("port %d", p.PortNum)
+			}
+		}
+	}
+	names := []string{strings.ToLower(p.Process)}
+	if p.Executable != "" {
+		names = append(names, strings.ToLower(filepath.Base(p.Executable)))
+	}
+	for _, pattern := range r.Processes {
+		for _, name := range names {
+			if globMatch(pattern, name) {
+				return "process " + name
+			}
+		}
+	}
+	if p.DockerImage != "" {
+		for _, pattern := range r.Images {
+			if globMatch(pattern, strings.ToLower(p.DockerImage)) {
+				return "image " + p.DockerImage
+			}
+		}
+	}
+	if p.SystemdUnit != "" {
+		for _, pattern := range r.Units {
+			if globMatch(pattern, strings.ToLower(p.SystemdUnit)) {
+				return "unit " + p.SystemdUnit
+			}
+		}
+	}
+	return ""
+}
+
+func globMatch(pattern, name string) bool {
+	if name == "" {
+		return false
+	}
+	ok, err := path.Match(strings.ToLower(pattern), name)
+	return err == nil && ok
+}
+
+// portInSpec reports whether port matches "5432" or "8000-8100".
+func portInSpec(port uint16, spec string) bool {
+	from, to, ok := parsePortSpec(spec)
+	return ok && int(port) >= from && int(port) <= to
+}
+
+// parsePortSpec parses "5432" or "8000-8100"; ok is false unless both bounds are ports and from <= to.
+func parsePortSpec(spec string) (from, to int, ok bool) {
+	lo, hi, found := strings.Cut(strings.TrimSpace(spec), "-")
+	from, err := strconv.Atoi(strings.TrimSpace(lo))
+	if err != nil {
+		return 0, 0, false
+	}
+	to = from
+	if found {
+		if to, err = strconv.Atoi(strings.TrimSpace(hi)); err != nil {
+			return 0, 0, false
+		}
+	}
+	return from, to, from >= 0 && from <= to && to <= 65535
+}
+
+// ValidatePolicy reports malformed patterns so config mistakes fail loudly at startup.
+func ValidatePolicy(pol Policy) error {
+	for _, rules := range []PolicyRules{pol.Protected, pol.Deny} {
+		for _, spec := range rules.Ports {
+			if _, _, ok := parsePortSpec(spec); !ok {
+				return fmt.Errorf("invalid port rule %q", spec)
+			}
+		}
+		for _, list := range [][]string{rules.Processes, rules.Images, rules.Units} {
+			for _, pattern := range list {
+				if _, err := path.Match(pattern, ""); err != nil {
+					return fmt.Errorf("invalid pattern %q: %w", pattern, err)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+var (
+	policyMu     sync.RWMutex
+	activePolicy = &Policy{}
+)
+
+// SetPolicy installs the policy enforced by Signal, the kill helpers and container actions.
+func SetPolicy(pol Policy) {
+	policyMu.Lock()
+	activePolicy = &pol
+	policyMu.Unlock()
+}
+
+// CheckPolicy evaluates the installed policy for p. Front-ends call this before offering
+// an action, and must collect typed confirmation for Confirm verdicts themselves.
+func CheckPolicy(p Port) Verdict {
+	policyMu.RLock()
+	pol := activePolicy
+	policyMu.RUnlock()
+	return pol.Check(p)
+}
+
+// ReadOnly reports whether the installed policy disables all destructive actions.
+func ReadOnly() bool {
+	policyMu.RLock()
+	defer policyMu.RUnlock()
+	return activePolicy.ReadOnly
+}
+
+// enforcePolicy refuses Deny verdicts. Confirm is allowed here: the front-end already asked.
+func enforcePolicy(p Port) error {
+	if v := CheckPolicy(p); v.Decision == Deny {
+		return &ErrPolicyDenied{Reason: v.Reason}
+	}
+	return nil
+}
+
+// CheckPolicyPorts returns the strictest verdict for p's process over p.PortNum and every other
+// port it holds, so signalling it cannot take down a protected listener unasked.
+func CheckPolicyPorts(p Port, held []uint16) Verdict {
+	worst := CheckPolicy(p)
+	for _, port := range held {
+		c := p
+		c.PortNum = port
+		if v := CheckPolicy(c); v.Decision > worst.Decision {
+			worst = v
+		}
+	}
+	return worst
+}
+
+// PolicyVerdict returns the strictest verdict over every process a scoped kill would signal,
+// including the other ports they hold (see CheckPolicyPorts).
+func (plan KillPlan) PolicyVerdict() Verdict {
+	worst := Verdict{Decision: Allow}
+	for _, t := range plan.snapshots() {
+		if v := CheckPolicyPorts(t, plan.Ports[t.PID]); v.Decision > worst.Decision {
+			worst = v
+		}
+	}
+	return worst
+}
@@ -0,0 +1,67 @@
+package ports
+
+import "testing"
+
+func TestPolicyCheck(t *testing.T) {
+	pol := Policy{
+		Protected: PolicyRules{Ports: []string{"5432", "8000-8100"}, Images: []string{"postgres:*"}},
+		Deny:      PolicyRules{Units: []string{"nginx.service"}},
+	}
+	tests := []struct {
+		name string
+		p    Port
+		want Decision
+	}{
+		{"dev server", Port{PortNum: 3000, Process: "node"}, Allow},
+		{"protected port", Port{PortNum: 5432, Process: "postgres"}, Confirm},
+		{"protected range", Port{PortNum: 8080, Process: "python3"}, Confirm},
+		{"protected image", Port{PortNum: 15432, DockerImage: "postgres:16"}, Confirm},
+		{"built-in deny", Port{PortNum: 22, Process: "sshd"}, Deny},
+		{"deny by executable", Port{PortNum: 2222, Process: "sshd: /usr/sbin/s", Executable: "/usr/sbin/sshd"}, Deny},
+		{"deny by unit beats protection", Port{PortNum: 8000, Process: "nginx", SystemdUnit: "nginx.service"}, Deny},
+	}
+	for _, tt := range tests {
+		if got := pol.Check(tt.p); got.Decision != tt.want {
+			t.Errorf("%s: Check = %+v, want decision %d", tt.name, got, tt.want)
+		}
+	}
+	pol.ReadOnly = true
+	if got := pol.Check(Port{PortNum: 3000, Process: "node"}); got.Decision != Deny {
+		t.Errorf("read-only: Check = %+v, want Deny", got)
+	}
+}
+
+func TestCheckPolicyPorts(t *testing.T) {
+	SetPolicy(Policy{Protected: PolicyRules{Ports: []string{"5432"}}})
+	defer SetPolicy(Policy{})
+	p := Port{PID: 7, PortNum: 3000, Process: "node"}
+	if v := CheckPolicyPorts(p, []uint16{3000, 3001}); v.Decision != Allow {
+		t.Errorf("unprotected ports: %+v", v)
+	}
+	// The same process also holds 5432: the verdict and the port to type come from 5432.
+	if v := CheckPolicyPorts(p, []uint16{3000, 5432}); v.Decision != Confirm || v.Port != 5432 {
+		t.Errorf("other protected port: %+v, want Confirm on 5432", v)
+	}
+}
+
+func TestValidatePolicy(t *testing.T) {
+	tests := []struct {
+		spec string
+		ok   bool
+	}{
+		{"5432", true},
+		{"8000-8100", true},
+		{" 8000 - 8100 ", true},
+		{"abc", false},
+		{"8000-abc", false},
+		{"8100-8000", false},
+		{"8000-", false},
+		{"70000", false},
+	}
+	for _, tt := range tests {
+		err := ValidatePolicy(Policy{Deny: PolicyRules{Ports: []string{tt.spec}}})
+		if (err == nil) != tt.ok {
+			t.Errorf("ValidatePolicy(%q) = %v, want ok=%v", tt.spec, err, tt.ok)
+		}
+	}
+}
@@ -0,0 +1,237 @@
+package ports
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ProcessInfo is one entry of the process table (any process, not only listeners).
+type ProcessInfo struct {
+	PID       int
+	PPID      int
+	PGID      int
+	Name      string
+	StartTime time.Time
+}
+
+// snapshot returns a Port carrying the identity of the process, so it can go through Signal.
+func (pi ProcessInfo) snapshot() Port {
+	return Port{PID: pi.PID, PPID: pi.PPID, Process: pi.Name, StartTime: pi.StartTime}
+}
+
+// KillScope selects which processes a kill affects.
+type KillScope int
+
+const (
+	ScopeProcess KillScope = iota // only the listening PID
+	ScopeGroup                    // every process in its process group
+	ScopeTree                     // the launcher (npm, turbo, concurrently, ...) and everything under it
+)
+
+func (s KillScope) String() string {
+	switch s {
+	case ScopeGroup:
+		return "process group"
+	case ScopeTree:
+		return "entire tree"
+	default:
+		return "process only"
+	}
+}
+
+// launcherNames are processes that start dev servers and may restart them or hold sibling ports.
+var launcherNames = map[string]bool{
+	"npm": true, "npx": true, "yarn": true, "pnpm": true, "bun": true,
+	"turbo": true, "concurrently": true, "nodemon": true, "nx": true,
+	"foreman": true, "overmind": true, "honcho": true, "hivemind": true,
+	"air": true, "watchexec": true, "cargo-watch": true, "reflex": true,
+}
+
+// glueNames are processes launchers use to run scripts (sh -c "next dev", node wrappers).
+// They only join the tree when their own parent is a launcher, so an interactive shell is never included.
+var glueNames = map[string]bool{"sh": true, "bash": true, "dash": true, "zsh": true, "node": true}
+
+// isLauncher matches names like "npm run dev" (npm sets its process title) as well as "npm".
+func isLauncher(name string) bool {
+	first := strings.ToLower(strings.Fields(name + " ")[0])
+	return launcherNames[first]
+}
+
+func isGlue(name string) bool {
+	first := strings.ToLower(strings.Fields(name + " ")[0])
+	return glueNames[first]
+}
+
+// KillPlan is every process a scoped kill will signal, in signal order (children first).
+type KillPlan struct {
+	Scope   KillScope
+	Root    ProcessInfo      // the listener (process), group leader (group), or launcher (tree)
+	Targets []ProcessInfo    // in signal order: deepest descendants first, Root's level last
+	Ports   map[int][]uint16 // listening ports per target PID, from the listing passed to PlanKill
+
+	listener Port // the selected row; its snapshot (with Executable) is used for its own PID
+}
+
+// PlanKill resolves the processes affected by killing p with the given scope.
+// listening is the current port list, used to preview which ports each target holds.
+// PID 1 and TAPAS itself (and its ancestors) are never included.
+func PlanKill(p Port, scope KillScope, listening []Port) (KillPlan, error) {
+	plan := KillPlan{Scope: scope, Ports: make(map[int][]uint16), listener: p}
+	for _, lp := range listening {
+		if lp.PID > 0 {
+			plan.Ports[lp.PID] = append(plan.Ports[lp.PID], lp.PortNum)
+		}
+	}
+	self := ProcessInfo{PID: p.PID, PPID: p.PPID, Name: p.Process, StartTime: p.StartTime}
+	if scope == ScopeProcess {
+		plan.Root = self
+		plan.Targets = []ProcessInfo{self}
+		return plan, nil
+	}
+	procs, err := listProcesses()
+	if err != nil {
+		return plan, err
+	}
+	byPID := make(map[int]ProcessInfo, len(procs))
+	children := make(map[int][]int)
+	for _, pi := range procs {
+		byPID[pi.PID] = pi
+		children[pi.PPID] = append(children[pi.PPID], pi.PID)
+	}
+	target, ok := byPID[p.PID]
+	if !ok {
+		return plan, fmt.Errorf("process %d already exited", p.PID)
+	}
+	protected := protectedPIDs(byPID)
+	depth := make(map[int]int)
+
+	switch scope {
+	case ScopeGroup:
+		plan.Root = target
+		if leader, ok := byPID[target.PGID]; ok {
+			plan.Root = leader
+		}
+		for _, pi := range procs {
+			if pi.PGID == target.PGID && !protected[pi.PID] {
+				plan.Targets = append(plan.Targets, pi)
+				depth[pi.PID] = depthOf(pi.PID, byPID)
+			}
+		}
+	case ScopeTree:
+		plan.Root = launcherOf(target, byPID)
+		queue := []int{plan.Root.PID}
+		depth[plan.Root.PID] = 0
+		for len(queue) > 0 {
+			pid := queue[0]
+			queue = queue[1:]
+			if !protected[pid] {
+				plan.Targets = append(plan.Targets, byPID[pid])
+			}
+			for _, child := range children[pid] {
+				depth[child] = depth[pid] + 1
+				queue = append(queue, child)
+			}
+		}
+	}
+	if len(plan.Targets) == 0 {
+		return plan, fmt.Errorf("no processes to signal (only protected processes in %s)", scope)
+	}
+	// Children first: deeper processes are signalled before their parents, so a launcher
+	// cannot respawn what we already stopped after it has been signalled itself.
+	sort.SliceStable(plan.Targets, func(i, j int) bool {
+		return depth[plan.Targets[i].PID] > depth[plan.Targets[j].PID]
+	})
+	return plan, nil
+}
+
+// launcherOf walks up from target through launchers (and the shells/node wrappers they spawn)
+// and returns the topmost one. Returns target itself when it was not started by a launcher.
+func launcherOf(target ProcessInfo, byPID map[int]ProcessInfo) ProcessInfo {
+	root := target
+	cur := target
+	for i := 0; i < 32; i++ { // bound the walk; process trees are shallow
+		parent, ok := byPID[cur.PPID]
+		if !ok || parent.PID <= 1 {
+			break
+		}
+		if isLauncher(parent.Name) {
+			root = parent
+		} else if isGlue(parent.Name) {
+			grand, ok := byPID[parent.PPID]
+			if !ok || !isLauncher(grand.Name) {
+				break
+			}
+		} else {
+			break
+		}
+		cur = parent
+	}
+	return root
+}
+
+// depthOf counts ancestors, used to order group members children-first.
+func depthOf(pid int, byPID map[int]ProcessInfo) int {
+	d := 0
+	for i := 0; i < 64; i++ {
+		pi, ok := byPID[pid]
+		if !ok || pi.PPID <= 1 {
+			break
+		}
+		pid = pi.PPID
+		d++
+	}
+	return d
+}
+
+// protectedPIDs returns PID 1 and TAPAS with all of its ancestors (its shell, terminal, tmux).
+func protectedPIDs(byPID map[int]ProcessInfo) map[int]bool {
+	protected := map[int]bool{1: true}
+	pid := os.Getpid()
+	for i := 0; i < 64 && pid > 1; i++ {
+		protected[pid] = true
+		pi, ok := byPID[pid]
+		if !ok {
+			break
+		}
+		pid = pi.PPID
+	}
+	return protected
+}
+
+// AffectedPorts returns every listening port held by the plan's targets, sorted.
+func (plan KillPlan) AffectedPorts() []uint16 {
+	var out []uint16
+	seen := make(map[uint16]bool) // IPv4 and IPv6 sockets on the same port are listed twice
+	for _, t := range plan.Targets {
+		for _, port := range plan.Ports[t.PID] {
+			if !seen[port] {
+				seen[port] = true
+				out = append(out, port)
+			}
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i] < out[j] })
+	return out
+}
+
+// snapshots returns the targets as Ports for Signal, in signal order.
+func (plan KillPlan) snapshots() []Port {
+	out := make([]Port, 0, len(plan.Targets))
+	for _, t := range plan.Targets {
+		if t.PID == plan.listener.PID {
+			out = append(out, plan.listener)
+			continue
+		}
+		out = append(out, t.snapshot())
+	}
+	return out
+}
+
+// ProcessUsage is the resource use of one process, for metrics.
+type ProcessUsage struct {
+	CPUSeconds float64 // user + system CPU time since start
+	RSSBytes   uint64  // resident memory
+}
@@ -0,0 +1,94 @@
+//go:build darwin
+
+package ports
+
+import (
+	"bufio"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// listProcesses runs ps for every process: pid, ppid, pgid, lstart (5 fields), comm.
+func listProcesses() ([]ProcessInfo, error) {
+	cmd := exec.Command("ps", "-axo", "pid=,ppid=,pgid=,lstart=,comm=")
+	cmd.Env = []string{"LC_ALL=C"}
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, err
+	}
+	var list []ProcessInfo
+	sc := bufio.NewScanner(strings.NewReader(string(out)))
+	for sc.Scan() {
+		fields := strings.Fields(sc.Text())
+		if len(fields) < 9 {
+			continue
+		}
+		pid, err := strconv.Atoi(fields[0])
+		if err != nil {
+			continue
+		}
+		info := ProcessInfo{PID: pid}
+		info.PPID, _ = strconv.Atoi(fields[1])
+		info.PGID, _ = strconv.Atoi(fields[2])
+		info.StartTime, _ = time.ParseInLocation("Mon Jan 2 15:04:05 2006", strings.Join(fields[3:8], " "), time.Local)
+		info.Name = lastPathComponent(strings.Join(fields[8:], " "))
+		list = append(list, info)
+	}
+	return list, sc.Err()
+}
+
+// processArgv is not available without cgo on macOS; callers fall back to splitting Command.
+func processArgv(pid int) []string {
+	return nil
+}
+
+// processEnviron is not available on macOS (ps eww output cannot be split reliably).
+func processEnviron(pid int) []string {
+	return nil
+}
+
+// processUsage runs ps once for CPU time and RSS of pids. Unknown PIDs are left out.
+func processUsage(pids []int) map[int]ProcessUsage {
+	usage := make(map[int]ProcessUsage, len(pids))
+	if len(pids) == 0 {
+		return usage
+	}
+	list := make([]string, len(pids))
+	for i, pid := range pids {
+		list[i] = strconv.Itoa(pid)
+	}
+	cmd := exec.Command("ps", "-o", "pid=,rss=,time=", "-p", strings.Join(list, ","))
+	cmd.Env = []string{"LC_ALL=C"}
+	out, _ := cmd.Output() // ps exits 1 when some PIDs are gone; the rest is still printed
+	sc := bufio.NewScanner(strings.NewReader(string(out)))
+	for sc.Scan() {
+		fields := strings.Fields(sc.Text())
+		if len(fields) < 3 {
+			continue
+		}
+		pid, err := strconv.Atoi(fields[0])
+		if err != nil {
+			continue
+		}
+		rssKB, _ := strconv.ParseUint(fields[1], 10, 64)
+		usage[pid] = ProcessUsage{CPUSeconds: parseCPUTime(fields[2]), RSSBytes: rssKB * 1024}
+	}
+	return usage
+}
+
+// parseCPUTime parses ps time output: "[[dd-]hh:]mm:ss.cc".
+func parseCPUTime(s string) float64 {
+	var days float64
+	if d, rest, ok := strings.Cut(s, "-"); ok {
+		days, _ = strconv.ParseFloat(d, 64)
+		s = rest
+	}
+	var total float64
+	for _, part := range strings.Split(s, ":") {
+		v, _ := strconv.ParseFloat(part, 64)
+		total = total*60 + v
+	}
+	return days*86400 + total
+}
@@ -0,0 +1,101 @@
+//go:build linux
+
+package ports
+
+import (
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// listProcesses scans /proc for every process we can see.
+func listProcesses() ([]ProcessInfo, error) {
+	entries, err := os.ReadDir("/proc")
+	if err != nil {
+		return nil, err
+	}
+	uptime, err := readUptimeSeconds()
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	var list []ProcessInfo
+	for _, e := range entries {
+		pid, err := strconv.Atoi(e.Name())
+		if err != nil || pid <= 0 {
+			continue
+		}
+		data, err := readFile("/proc/" + e.Name() + "/stat")
+		if err != nil {
+			continue
+		}
+		open, closeIdx := strings.Index(data, "("), strings.LastIndex(data, ")")
+		if open < 0 || closeIdx < open {
+			continue
+		}
+		fields := strings.Fields(data[closeIdx+1:])
+		if len(fields) < 20 {
+			continue
+		}
+		info := ProcessInfo{PID: pid, Name: data[open+1 : closeIdx]}
+		info.PPID, _ = strconv.Atoi(fields[1])
+		info.PGID, _ = strconv.Atoi(fields[2])
+		if jiffies, err := strconv.ParseUint(fields[19], 10, 64); err == nil {
+			elapsed := uptime - float64(jiffies)/100.0
+			info.StartTime = now.Add(-time.Duration(elapsed * float64(time.Second)))
+		}
+		list = append(list, info)
+	}
+	return list, nil
+}
+
+// processArgv returns the exact argv of pid from /proc/<pid>/cmdline (nil if unreadable).
+func processArgv(pid int) []string {
+	if pid <= 0 {
+		return nil
+	}
+	data, err := os.ReadFile("/proc/" + strconv.Itoa(pid) + "/cmdline")
+	if err != nil || len(data) == 0 {
+		return nil
+	}
+	return strings.Split(strings.TrimRight(string(data), "\x00"), "\x00")
+}
+
+// processEnviron returns the environment of pid (readable for our own processes only).
+func processEnviron(pid int) []string {
+	if pid <= 0 {
+		return nil
+	}
+	data, err := os.ReadFile("/proc/" + strconv.Itoa(pid) + "/environ")
+	if err != nil || len(data) == 0 {
+		return nil
+	}
+	return strings.Split(strings.TrimRight(string(data), "\x00"), "\x00")
+}
+
+// processUsage reads CPU time and RSS from /proc/<pid>/stat. Unreadable PIDs are left out.
+func processUsage(pids []int) map[int]ProcessUsage {
+	usage := make(map[int]ProcessUsage, len(pids))
+	pageSize := uint64(os.Getpagesize())
+	for _, pid := range pids {
+		data, err := readFile("/proc/" + strconv.Itoa(pid) + "/stat")
+		if err != nil {
+			continue
+		}
+		closeIdx := strings.LastIndex(data, ")")
+		if closeIdx < 0 {
+			continue
+		}
+		fields := strings.Fields(data[closeIdx+1:])
+		if len(fields) < 22 {
+			continue
+		}
+		// Fields 14 and 15 are utime and stime in clock ticks (USER_HZ, 100 on Linux); 24 is RSS in pages.
+		utime, _ := strconv.ParseUint(fields[11], 10, 64)
+		stime, _ := strconv.ParseUint(fields[12], 10, 64)
+		rss, _ := strconv.ParseUint(fields[21], 10, 64)
+		usage[pid] = ProcessUsage{CPUSeconds: float64(utime+stime) / 100.0, RSSBytes: rss * pageSize}
+	}
+	return usage
+}
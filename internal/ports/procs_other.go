@@ -0,0 +1,21 @@
+//go:build !darwin && !linux
+
+package ports
+
+import "errors"
+
+func listProcesses() ([]ProcessInfo, error) {
+	return nil, errors.New("TAPAS is supported on macOS and Linux only")
+}
+
+func processArgv(pid int) []string {
+	return nil
+}
+
+func processEnviron(pid int) []string {
+	return nil
+}
+
+func processUsage(pids []int) map[int]ProcessUsage {
+	return nil
+}
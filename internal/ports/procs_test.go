@@ -0,0 +1,20 @@
+package ports
+
+import "testing"
+
+func TestLauncherOf(t *testing.T) {
+	byPID := map[int]ProcessInfo{
+		10: {PID: 10, PPID: 1, Name: "bash"},
+		20: {PID: 20, PPID: 10, Name: "npm run dev"},
+		30: {PID: 30, PPID: 20, Name: "sh"},
+		40: {PID: 40, PPID: 30, Name: "node"},
+		50: {PID: 50, PPID: 10, Name: "node"},
+	}
+	if got := launcherOf(byPID[40], byPID); got.PID != 20 {
+		t.Errorf("launcherOf(node under npm) = %d, want 20 (npm)", got.PID)
+	}
+	// Started directly from an interactive shell: the shell is never part of the tree.
+	if got := launcherOf(byPID[50], byPID); got.PID != 50 {
+		t.Errorf("launcherOf(node under bash) = %d, want 50 (itself)", got.PID)
+	}
+}
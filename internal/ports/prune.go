@@ -0,0 +1,45 @@
+package ports
+
+import (
+	"strings"
+	"time"
+)
+
+// PruneCriteria selects listeners for tapas prune. Every set field must match.
+type PruneCriteria struct {
+	Idle      time.Duration // no connections observed for at least this long (needs activity)
+	OlderThan time.Duration // running for at least this long
+	Orphaned  bool          // reparented to init (see StaleOrphaned)
+	Project   string        // project name or cwd base name, case-insensitive
+	DevOnly   bool          // only dev servers: Framework or Environment detected
+}
+
+// Match reports whether p meets every criterion. act is the listener's observed activity;
+// without it, an idle criterion never matches (unknown is not idle).
+func (c PruneCriteria) Match(p Port, act *Activity, now time.Time) bool {
+	if c.DevOnly && p.Framework == "" && p.Environment == "" {
+		return false
+	}
+	if c.Orphaned && !hasStaleReason(p, StaleOrphaned) {
+		return false
+	}
+	if c.OlderThan > 0 && (p.StartTime.IsZero() || now.Sub(p.StartTime) < c.OlderThan) {
+		return false
+	}
+	if c.Idle > 0 && (act == nil || act.IdleFor(now) < c.Idle) {
+		return false
+	}
+	if c.Project != "" && !strings.EqualFold(c.Project, p.ProjectDisplayName) && !strings.EqualFold(c.Project, p.Project()) {
+		return false
+	}
+	return true
+}
+
+func hasStaleReason(p Port, reason StaleReason) bool {
+	for _, r := range p.StaleReasons {
+		if r == reason {
+			return true
+		}
+	}
+	return false
+}
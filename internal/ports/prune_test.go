@@ -0,0 +1,35 @@
+package ports
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPruneCriteriaMatch(t *testing.T) {
+	now := time.Now()
+	dev := Port{PID: 10, PortNum: 3000, Framework: "Vite", WorkingDir: "/src/shop", StartTime: now.Add(-48 * time.Hour)}
+	idle := &Activity{FirstSeen: now.Add(-10 * time.Hour), LastActive: now.Add(-5 * time.Hour)}
+	busy := &Activity{FirstSeen: now.Add(-10 * time.Hour), LastActive: now}
+
+	tests := []struct {
+		name string
+		c    PruneCriteria
+		p    Port
+		act  *Activity
+		want bool
+	}{
+		{"old dev server", PruneCriteria{OlderThan: 24 * time.Hour, DevOnly: true}, dev, nil, true},
+		{"not a dev server", PruneCriteria{DevOnly: true}, Port{PID: 11, Process: "postgres"}, nil, false},
+		{"idle long enough", PruneCriteria{Idle: 4 * time.Hour}, dev, idle, true},
+		{"recently active", PruneCriteria{Idle: 4 * time.Hour}, dev, busy, false},
+		{"idle unknown is not idle", PruneCriteria{Idle: time.Hour}, dev, nil, false},
+		{"project by cwd", PruneCriteria{Project: "SHOP"}, dev, nil, true},
+		{"other project", PruneCriteria{Project: "blog"}, dev, nil, false},
+		{"orphaned required", PruneCriteria{Orphaned: true}, dev, nil, false},
+	}
+	for _, tt := range tests {
+		if got := tt.c.Match(tt.p, tt.act, now); got != tt.want {
+			t.Errorf("%s: Match = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
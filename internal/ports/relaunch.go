@@ -0,0 +1,249 @@
+package ports
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// Relaunch record reasons.
+const (
+	RelaunchKilled = "killed" // killed from TAPAS; captured before the signal, with environment
+	RelaunchExited = "exited" // disappeared between refreshes (crashed or stopped elsewhere); no environment
+)
+
+// maxRelaunchRecords caps the recently killed list; the oldest records are dropped.
+const maxRelaunchRecords = 20
+
+// RelaunchRecord is everything needed to start a dev server again: argv, cwd and selected env.
+type RelaunchRecord struct {
+	PID     int       `json:"pid"`
+	Port    uint16    `json:"port"`
+	Process string    `json:"process"`
+	Project string    `json:"project,omitempty"`
+	Argv    []string  `json:"argv"`
+	Cwd     string    `json:"cwd"`
+	Env     []string  `json:"env,omitempty"`
+	Reason  string    `json:"reason"`
+	At      time.Time `json:"at"`
+}
+
+// relaunchEnv are the environment variables kept for a relaunch. Everything else (tokens,
+// credentials, session variables) is dropped; the rest comes from the environment of TAPAS.
+var relaunchEnv = map[string]bool{
+	"PATH": true, "PORT": true, "HOST": true, "NODE_ENV": true, "NODE_OPTIONS": true,
+	"RAILS_ENV": true, "RACK_ENV": true, "APP_ENV": true, "FLASK_APP": true, "FLASK_ENV": true,
+	"FLASK_DEBUG": true, "DJANGO_SETTINGS_MODULE": true, "VIRTUAL_ENV": true, "PYTHONPATH": true,
+	"GOPATH": true, "GOFLAGS": true, "CARGO_HOME": true, "RUSTUP_HOME": true,
+}
+
+// NewRelaunchRecord captures how to start p again. Call it before the kill: afterwards the
+// environment is gone. Returns false for rows that cannot be relaunched (containers, unknown argv).
+func NewRelaunchRecord(p Port, reason string) (RelaunchRecord, bool) {
+	if p.PID <= 0 || p.DockerContainerName != "" || p.InDocker || p.WorkingDir == "" {
+		return RelaunchRecord{}, false
+	}
+	argv := p.Args
+	if len(argv) == 0 {
+		argv = processArgv(p.PID)
+	}
+	if len(argv) == 0 {
+		argv = strings.Fields(p.Command) // macOS: ps joins argv; quoting is lost
+	}
+	if len(argv) == 0 {
+		return RelaunchRecord{}, false
+	}
+	r := RelaunchRecord{
+		PID:     p.PID,
+		Port:    p.PortNum,
+		Process: p.Process,
+		Project: p.Project(),
+		Argv:    argv,
+		Cwd:     p.WorkingDir,
+		Reason:  reason,
+		At:      time.Now().Truncate(time.Second),
+	}
+	if reason == RelaunchKilled {
+		for _, kv := range processEnviron(p.PID) {
+			if name, _, ok := strings.Cut(kv, "="); ok && relaunchEnv[name] {
+				r.Env = append(r.Env, kv)
+			}
+		}
+	}
+	return r, true
+}
+
+// CommandLine returns argv joined for display, redacted.
+func (r RelaunchRecord) CommandLine() string {
+	return Redact(strings.Join(r.Argv, " "))
+}
+
+// sameCommand reports whether both records start the same server (same cwd and argv).
+func (r RelaunchRecord) sameCommand(o RelaunchRecord) bool {
+	return r.Cwd == o.Cwd && strings.Join(r.Argv, "\x00") == strings.Join(o.Argv, "\x00")
+}
+
+// RelaunchStore keeps relaunch records in a JSON state file (mode 0600: argv may hold secrets).
+type RelaunchStore struct {
+	path string
+	mu   sync.Mutex
+}
+
+// NewRelaunchStore returns a store backed by relaunch.json in dir. Output of relaunched
+// servers goes to dir/logs.
+func NewRelaunchStore(dir string) *RelaunchStore {
+	return &RelaunchStore{path: filepath.Join(dir, "relaunch.json")}
+}
+
+// List returns the records, newest first. A missing state file is an empty list.
+func (s *RelaunchStore) List() ([]RelaunchRecord, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.load()
+}
+
+// Add stores r. One record per process: an existing record for the same PID wins (the one
+// captured before the kill has the environment). Older records of the same command are replaced.
+func (s *RelaunchStore) Add(r RelaunchRecord) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	list, err := s.load()
+	if err != nil {
+		return err
+	}
+	out := []RelaunchRecord{r}
+	for _, old := range list {
+		if old.PID == r.PID && old.sameCommand(r) {
+			return nil
+		}
+		if !old.sameCommand(r) {
+			out = append(out, old)
+		}
+	}
+	if len(out) > maxRelaunchRecords {
+		out = out[:maxRelaunchRecords]
+	}
+	return s.save(out)
+}
+
+// Remove deletes the record for r's process, e.g. after it was relaunched.
+func (s *RelaunchStore) Remove(r RelaunchRecord) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	list, err := s.load()
+	if err != nil {
+		return err
+	}
+	out := list[:0]
+	for _, old := range list {
+		if old.PID != r.PID || !old.sameCommand(r) {
+			out = append(out, old)
+		}
+	}
+	return s.save(out)
+}
+
+func (s *RelaunchStore) load() ([]RelaunchRecord, error) {
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var list []RelaunchRecord
+	if err := json.Unmarshal(data, &list); err != nil {
+		return nil, fmt.Errorf("%s: %w", s.path, err)
+	}
+	return list, nil
+}
+
+// save writes the list atomically so a crash never leaves half a file.
+func (s *RelaunchStore) save(list []RelaunchRecord) error {
+	return writeFileAtomic(s.path, list)
+}
+
+// Relaunch starts r again, detached in its own session, in the same cwd. Output is appended to
+// logs/port-<port>.log next to the state file. Refuses while the port is still in use. Audited.
+func (s *RelaunchStore) Relaunch(r RelaunchRecord) (pid int, logPath string, err error) {
+	defer func() { auditRelaunch(r, pid, err) }()
+	if ReadOnly() {
+		return 0, "", &ErrPolicyDenied{Reason: "read-only mode"}
+	}
+	if r.Port > 0 {
+		if listening, err := PortListening(r.Port); err == nil && listening {
+			return 0, "", fmt.Errorf("port %d is in use", r.Port)
+		}
+	}
+	if len(r.Argv) == 0 {
+		return 0, "", errors.New("no command recorded")
+	}
+	if _, err := os.Stat(r.Cwd); err != nil {
+		return 0, "", fmt.Errorf("working directory is gone: %s", r.Cwd)
+	}
+	env := append(os.Environ(), r.Env...) // later entries win
+	path, err := lookPathIn(r.Argv[0], r.Cwd, env)
+	if err != nil {
+		return 0, "", err
+	}
+	logDir := filepath.Join(filepath.Dir(s.path), "logs")
+	if err := os.MkdirAll(logDir, 0o700); err != nil {
+		return 0, "", err
+	}
+	logPath = filepath.Join(logDir, fmt.Sprintf("port-%d.log", r.Port))
+	logFile, err := os.OpenFile(logPath, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o600)
+	if err != nil {
+		return 0, "", err
+	}
+	defer logFile.Close() // the child keeps its own descriptor
+	fmt.Fprintf(logFile, "--- %s relaunched by tapas: %s\n", time.Now().Format(time.RFC3339), r.CommandLine())
+
+	cmd := exec.Command(path, r.Argv[1:]...)
+	cmd.Args[0] = r.Argv[0]
+	cmd.Dir = r.Cwd
+	cmd.Env = env
+	cmd.Stdout = logFile
+	cmd.Stderr = logFile
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setsid: true} // survives TAPAS and its terminal
+	if err := cmd.Start(); err != nil {
+		return 0, "", err
+	}
+	go cmd.Wait() // reap it if it exits while TAPAS is still running
+	return cmd.Process.Pid, logPath, nil
+}
+
+// lookPathIn resolves argv0 like a shell would with the recorded PATH (and relative to cwd).
+func lookPathIn(argv0, cwd string, env []string) (string, error) {
+	if strings.Contains(argv0, "/") {
+		if !filepath.IsAbs(argv0) {
+			argv0 = filepath.Join(cwd, argv0)
+		}
+		return argv0, nil
+	}
+	pathEnv := ""
+	for _, kv := range env {
+		if strings.HasPrefix(kv, "PATH=") {
+			pathEnv = kv[len("PATH="):]
+		}
+	}
+	for _, dir := range filepath.SplitList(pathEnv) {
+		if dir == "" {
+			dir = "."
+		}
+		if !filepath.IsAbs(dir) {
+			dir = filepath.Join(cwd, dir)
+		}
+		candidate := filepath.Join(dir, argv0)
+		if fi, err := os.Stat(candidate); err == nil && !fi.IsDir() && fi.Mode()&0o111 != 0 {
+			return candidate, nil
+		}
+	}
+	return "", fmt.Errorf("%s: command not found", argv0)
+}
@@ -0,0 +1,315 @@
+package ports
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Remediation is a concrete fix for a listener bound to all interfaces, derived from the command
+// line that started it: the same command bound to localhost, or the setting to change.
+type Remediation struct {
+	How  string `json:"how"`            // e.g. "restart Vite bound to localhost"
+	Seen string `json:"seen,omitempty"` // the command line (redacted) or port mapping the fix is based on
+	Fix  string `json:"fix"`            // the command to run instead, or the config change
+}
+
+// Remediate returns a fix for p when it listens on all interfaces and TAPAS recognises what
+// started it; nil otherwise. It only rewrites what it saw and never runs anything.
+func Remediate(p Port) *Remediation {
+	if !IsPublicBind(p.BindAddress) {
+		return nil
+	}
+	if p.DockerContainerName != "" {
+		return dockerRemedy(p)
+	}
+	args := strings.Fields(p.SafeCommand())
+	if len(args) == 0 {
+		return nil
+	}
+	for _, r := range remedies {
+		if fix, ok := r.fix(args, p); ok {
+			return &Remediation{How: r.how, Seen: strings.Join(args, " "), Fix: fix}
+		}
+	}
+	return nil
+}
+
+// remedies are tried in order; specific tools first, then any long host flag on the command line.
+var remedies = []struct {
+	how string
+	fix func(args []string, p Port) (string, bool)
+}{
+	{"bind the Node inspector to localhost", func(args []string, p Port) (string, bool) {
+		if debuggerOf(p) != "Node inspector" {
+			return "", false
+		}
+		return replaceArg(args, inspectFlagRe, fmt.Sprintf("${1}=127.0.0.1:%d", p.PortNum))
+	}},
+	{"bind the JDWP agent to localhost", func(args []string, p Port) (string, bool) {
+		if debuggerOf(p) != "JDWP" {
+			return "", false
+		}
+		return replaceArg(args, jdwpAddressRe, fmt.Sprintf("${1}127.0.0.1:%d", p.PortNum))
+	}},
+	{"restart Next.js bound to localhost", func(args []string, p Port) (string, bool) {
+		i := argIndex(args, "next")
+		if i < 0 || i+1 >= len(args) || (args[i+1] != "dev" && args[i+1] != "start") {
+			return "", false
+		}
+		return join(setFlag(args, []string{"-H", "--hostname"}, loopback(0))), true
+	}},
+	{"restart Vite bound to localhost", func(args []string, p Port) (string, bool) {
+		if argIndex(args, "vite") < 0 {
+			return "", false
+		}
+		return join(setFlag(args, []string{"--host"}, loopback(0))), true
+	}},
+	{"restart Rails bound to localhost", func(args []string, p Port) (string, bool) {
+		i := argIndex(args, "rails")
+		if i < 0 || i+1 >= len(args) || (args[i+1] != "s" && args[i+1] != "server") {
+			return "", false
+		}
+		return join(setFlag(args, []string{"-b", "--binding"}, loopback(0))), true
+	}},
+	{"restart Puma bound to localhost", func(args []string, p Port) (string, bool) {
+		if argIndex(args, "puma") < 0 {
+			return "", false
+		}
+		return join(setFlag(args, []string{"-b", "--bind"}, func(old string) string {
+			if old == "" {
+				return fmt.Sprintf("tcp://127.0.0.1:%d", p.PortNum)
+			}
+			return loopback(0)(old)
+		})), true
+	}},
+	{"restart uvicorn bound to localhost", func(args []string, p Port) (string, bool) {
+		if argIndex(args, "uvicorn") < 0 {
+			return "", false
+		}
+		return join(setFlag(args, []string{"--host"}, loopback(0))), true
+	}},
+	{"restart gunicorn bound to localhost", func(args []string, p Port) (string, bool) {
+		if argIndex(args, "gunicorn") < 0 {
+			return "", false
+		}
+		return join(setFlag(args, []string{"-b", "--bind"}, loopback(p.PortNum))), true
+	}},
+	{"restart Flask bound to localhost", func(args []string, p Port) (string, bool) {
+		i := argIndex(args, "flask")
+		if i < 0 || i+1 >= len(args) || args[i+1] != "run" {
+			return "", false
+		}
+		return join(setFlag(args, []string{"--host", "-h"}, loopback(0))), true
+	}},
+	{"restart the Django dev server bound to localhost", func(args []string, p Port) (string, bool) {
+		i := argIndex(args, "runserver")
+		if i < 0 {
+			return "", false
+		}
+		out := append([]string(nil), args...)
+		if i+1 < len(out) && !strings.HasPrefix(out[i+1], "-") {
+			out[i+1] = loopback(p.PortNum)(out[i+1])
+			return join(out), true
+		}
+		out = append(out[:i+1], append([]string{fmt.Sprintf("127.0.0.1:%d", p.PortNum)}, out[i+1:]...)...)
+		return join(out), true
+	}},
+	{"restart http.server bound to localhost", func(args []string, p Port) (string, bool) {
+		if argIndex(args, "http.server") < 0 {
+			return "", false
+		}
+		return join(setFlag(args, []string{"--bind", "-b"}, loopback(0))), true
+	}},
+	{"bind PostgreSQL to localhost", func(args []string, p Port) (string, bool) {
+		if argIndex(args, "postgres", "postmaster") != 0 {
+			return "", false
+		}
+		if fix, ok := replaceArg(args, listenAddressesRe, "${1}localhost"); ok {
+			return fix, true
+		}
+		if hasFlag(args, "-h") {
+			return join(setFlag(args, []string{"-h"}, func(string) string { return "localhost" })), true
+		}
+		return "listen_addresses = 'localhost' in postgresql.conf (psql -c 'SHOW config_file'), then restart", true
+	}},
+	{"bind Redis to localhost", func(args []string, p Port) (string, bool) {
+		if argIndex(args, "redis-server") != 0 {
+			return "", false
+		}
+		if !hasFlag(args, "--bind") {
+			for _, a := range args[1:] {
+				if strings.HasSuffix(a, ".conf") {
+					return "bind 127.0.0.1 -::1 in " + a + ", then restart", true
+				}
+				// redis-server retitles itself "redis-server *:6379": the real arguments are gone.
+				if i := strings.LastIndex(a, ":"); i >= 0 && isDigits(a[i+1:]) {
+					return "bind 127.0.0.1 -::1 in redis.conf (redis-cli INFO server shows config_file), then restart", true
+				}
+			}
+		}
+		return join(setFlag(args, []string{"--bind"}, loopback(0))), true
+	}},
+	{"bind MySQL to localhost", func(args []string, p Port) (string, bool) {
+		if argIndex(args, "mysqld", "mariadbd") != 0 {
+			return "", false
+		}
+		if hasFlag(args, "--bind-address") {
+			return join(setFlag(args, []string{"--bind-address"}, loopback(0))), true
+		}
+		return "bind-address = 127.0.0.1 under [mysqld] in my.cnf, then restart", true
+	}},
+	{"bind MongoDB to localhost", func(args []string, p Port) (string, bool) {
+		if argIndex(args, "mongod") != 0 {
+			return "", false
+		}
+		out := make([]string, 0, len(args))
+		for _, a := range args {
+			if a != "--bind_ip_all" {
+				out = append(out, a)
+			}
+		}
+		return join(setFlag(out, []string{"--bind_ip"}, loopback(0))), true
+	}},
+	{"restart it bound to localhost", func(args []string, p Port) (string, bool) {
+		names := []string{"--host", "--hostname", "--bind", "--bind-address", "--ip", "--listen"}
+		for _, name := range names {
+			if hasFlag(args, name) {
+				return join(setFlag(args, []string{name}, loopback(0))), true
+			}
+		}
+		return "", false
+	}},
+	{"bind the dev server to localhost in the script that starts it", func(args []string, p Port) (string, bool) {
+		// next-server and friends retitle themselves; the flag belongs in the package.json script.
+		flag := map[string]string{"Next.js": "-H 127.0.0.1", "Vite": "--host 127.0.0.1"}[p.Framework]
+		if flag == "" {
+			return "", false
+		}
+		return fmt.Sprintf("add %s to the dev script in %s", flag, filepath.Join(orDot(p.WorkingDir), "package.json")), true
+	}},
+}
+
+var (
+	inspectFlagRe     = regexp.MustCompile(`^(--inspect(?:-brk|-wait)?)(?:=.*)?$`)
+	jdwpAddressRe     = regexp.MustCompile(`(address=)[^,]*`)
+	listenAddressesRe = regexp.MustCompile(`^(listen_addresses=).*`)
+)
+
+// dockerRemedy publishes the container's port on localhost only.
+func dockerRemedy(p Port) *Remediation {
+	inner := "CONTAINER_PORT"
+	if p.DockerContainerPort > 0 {
+		inner = strconv.Itoa(int(p.DockerContainerPort))
+	}
+	mapping := fmt.Sprintf("127.0.0.1:%d:%s", p.PortNum, inner)
+	return &Remediation{
+		How:  "publish the port on localhost only",
+		Seen: fmt.Sprintf("%s publishes %s:%d->%s", p.DockerContainerName, orAny(p.BindAddress), p.PortNum, inner),
+		Fix:  fmt.Sprintf("ports: [\"%s\"] in the compose file, or docker run -p %s", mapping, mapping),
+	}
+}
+
+// argIndex returns the index of the first argument whose base name is one of names, or -1.
+// Base names match node_modules/.bin/vite as well as vite.
+func argIndex(args []string, names ...string) int {
+	for i, a := range args {
+		base := strings.ToLower(filepath.Base(a))
+		for _, name := range names {
+			if base == name {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// hasFlag reports whether name appears as "name" or "name=value".
+func hasFlag(args []string, name string) bool {
+	for _, a := range args {
+		if a == name || strings.HasPrefix(a, name+"=") {
+			return true
+		}
+	}
+	return false
+}
+
+// setFlag returns args with the first flag in names set to value(old), keeping the "--flag v" or
+// "--flag=v" form found; names[0] is appended when none is present. A flag that is last or followed
+// by another flag is bare (vite --host) and gets the value inserted after it.
+func setFlag(args []string, names []string, value func(old string) string) []string {
+	out := append([]string(nil), args...)
+	for i, a := range out {
+		for _, name := range names {
+			if old, ok := strings.CutPrefix(a, name+"="); ok {
+				out[i] = name + "=" + value(old)
+				return out
+			}
+			if a != name {
+				continue
+			}
+			if i+1 < len(out) && !strings.HasPrefix(out[i+1], "-") {
+				out[i+1] = value(out[i+1])
+				return out
+			}
+			return append(out[:i+1], append([]string{value("")}, out[i+1:]...)...)
+		}
+	}
+	return append(out, names[0], value(""))
+}
+
+// replaceArg rewrites the first argument matching re; ok is false when none does.
+func replaceArg(args []string, re *regexp.Regexp, repl string) (string, bool) {
+	for i, a := range args {
+		if re.MatchString(a) {
+			out := append([]string(nil), args...)
+			out[i] = re.ReplaceAllString(a, repl)
+			return join(out), true
+		}
+	}
+	return "", false
+}
+
+// loopback returns a rewrite of a bind value to 127.0.0.1 that keeps its scheme and port:
+// "0.0.0.0:8000" -> "127.0.0.1:8000", "tcp://[::]:3000" -> "tcp://127.0.0.1:3000". An empty value
+// becomes 127.0.0.1, with port appended when it is non-zero.
+func loopback(port uint16) func(string) string {
+	return func(old string) string {
+		scheme := ""
+		if i := strings.Index(old, "://"); i >= 0 {
+			scheme, old = old[:i+3], old[i+3:]
+		}
+		if i := strings.LastIndex(old, ":"); i >= 0 && isDigits(old[i+1:]) {
+			return scheme + "127.0.0.1" + old[i:]
+		}
+		if old == "" && port > 0 {
+			return fmt.Sprintf("127.0.0.1:%d", port)
+		}
+		return scheme + "127.0.0.1"
+	}
+}
+
+func isDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+func orDot(dir string) string {
+	if dir == "" {
+		return "."
+	}
+	return dir
+}
+
+func join(args []string) string {
+	return strings.Join(args, " ")
+}
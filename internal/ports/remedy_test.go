@@ -0,0 +1,46 @@
+package ports
+
+import "testing"
+
+func TestRemediate(t *testing.T) {
+	tests := []struct {
+		name string
+		p    Port
+		want string // Fix; "" means no remediation
+	}{
+		{"local", Port{PortNum: 5173, BindAddress: "127.0.0.1", Command: "node node_modules/.bin/vite --host 0.0.0.0"}, ""},
+		{"vite bare host", Port{PortNum: 5173, Command: "node /app/node_modules/.bin/vite --host --port 5173"},
+			"node /app/node_modules/.bin/vite --host 127.0.0.1 --port 5173"},
+		{"next append", Port{PortNum: 3000, BindAddress: "::", Command: "node node_modules/.bin/next dev"},
+			"node node_modules/.bin/next dev -H 127.0.0.1"},
+		{"rails", Port{PortNum: 3000, BindAddress: "0.0.0.0", Command: "ruby bin/rails s -b 0.0.0.0 -p 3000"},
+			"ruby bin/rails s -b 127.0.0.1 -p 3000"},
+		{"uvicorn equals", Port{PortNum: 8000, BindAddress: "0.0.0.0", Command: "python -m uvicorn app:app --host=0.0.0.0"},
+			"python -m uvicorn app:app --host=127.0.0.1"},
+		{"gunicorn keeps port", Port{PortNum: 8000, BindAddress: "0.0.0.0", Command: "gunicorn -b 0.0.0.0:8000 app:app"},
+			"gunicorn -b 127.0.0.1:8000 app:app"},
+		{"django", Port{PortNum: 8000, BindAddress: "0.0.0.0", Command: "python manage.py runserver 0:8000"},
+			"python manage.py runserver 127.0.0.1:8000"},
+		{"inspector", Port{PortNum: 9229, BindAddress: "0.0.0.0", Command: "node --inspect=0.0.0.0:9229 server.js"},
+			"node --inspect=127.0.0.1:9229 server.js"},
+		{"postgres config", Port{PortNum: 5432, BindAddress: "0.0.0.0", Command: "/usr/lib/postgresql/16/bin/postgres -D /var/lib/postgresql/16/main"},
+			"listen_addresses = 'localhost' in postgresql.conf (psql -c 'SHOW config_file'), then restart"},
+		{"redis retitled", Port{PortNum: 6379, BindAddress: "0.0.0.0", Command: "redis-server *:6379"},
+			"bind 127.0.0.1 -::1 in redis.conf (redis-cli INFO server shows config_file), then restart"},
+		{"redis flags", Port{PortNum: 6379, BindAddress: "0.0.0.0", Command: "redis-server --port 6379"},
+			"redis-server --port 6379 --bind 127.0.0.1"},
+		{"compose", Port{PortNum: 15432, BindAddress: "0.0.0.0", DockerContainerName: "db", DockerContainerPort: 5432},
+			`ports: ["127.0.0.1:15432:5432"] in the compose file, or docker run -p 127.0.0.1:15432:5432`},
+		{"unknown", Port{PortNum: 7000, BindAddress: "0.0.0.0", Command: "./server"}, ""},
+	}
+	for _, tt := range tests {
+		r := Remediate(tt.p)
+		got := ""
+		if r != nil {
+			got = r.Fix
+		}
+		if got != tt.want {
+			t.Errorf("%s: fix %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
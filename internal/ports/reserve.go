@@ -0,0 +1,122 @@
+package ports
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// Reservation holds a suggested port for a project for a short while, so scripts generating .env
+// files for other projects at the same time are not handed the same port.
+type Reservation struct {
+	Port    uint16    `json:"port"`
+	Project string    `json:"project"`
+	Until   time.Time `json:"until"`
+}
+
+// ReservationStore keeps reservations in reservations.json in the state dir.
+// Nothing binds the port: a reservation only steers tapas port suggest.
+type ReservationStore struct {
+	path string
+	mu   sync.Mutex
+}
+
+// NewReservationStore returns a store backed by reservations.json in dir.
+func NewReservationStore(dir string) *ReservationStore {
+	return &ReservationStore{path: filepath.Join(dir, "reservations.json")}
+}
+
+// Suggest runs SuggestPort with the ports other projects hold and, when d > 0, reserves the result
+// for opts.Project. The store stays locked from the check to the reservation, across processes,
+// so concurrent runs for different projects are never handed the same port.
+func (s *ReservationStore) Suggest(opts SuggestOptions, d time.Duration) (Suggestion, error) {
+	var sug Suggestion
+	err := s.locked(func() error {
+		list, err := s.load(time.Now())
+		if err != nil {
+			return err
+		}
+		opts.Held = heldBy(list, opts.Project)
+		if sug, err = SuggestPort(opts); err != nil || d <= 0 {
+			return err
+		}
+		_, err = s.reserve(sug.Port, opts.Project, d)
+		return err
+	})
+	return sug, err
+}
+
+// locked runs fn holding the store's mutex and an exclusive lock on reservations.json.lock, which
+// serialises tapas processes sharing the state dir.
+func (s *ReservationStore) locked(fn func() error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o700); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(s.path+".lock", os.O_CREATE|os.O_RDWR, 0o600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if err := syscall.Flock(int(f.Fd()), syscall.LOCK_EX); err != nil {
+		return err
+	}
+	defer syscall.Flock(int(f.Fd()), syscall.LOCK_UN)
+	return fn()
+}
+
+// reserve writes the reservation; the caller holds the lock.
+func (s *ReservationStore) reserve(port uint16, project string, d time.Duration) (Reservation, error) {
+	now := time.Now()
+	list, err := s.load(now)
+	if err != nil {
+		return Reservation{}, err
+	}
+	res := Reservation{Port: port, Project: project, Until: now.Add(d).Truncate(time.Second)}
+	out := []Reservation{res}
+	for _, r := range list {
+		if r.Port != port && !strings.EqualFold(r.Project, project) {
+			out = append(out, r)
+		}
+	}
+	return res, writeFileAtomic(s.path, out)
+}
+
+// heldBy maps the ports in list reserved by projects other than project to their holder.
+func heldBy(list []Reservation, project string) map[uint16]string {
+	held := make(map[uint16]string)
+	for _, r := range list {
+		if !strings.EqualFold(r.Project, project) {
+			held[r.Port] = r.Project
+		}
+	}
+	return held
+}
+
+// load returns the unexpired reservations.
+func (s *ReservationStore) load(now time.Time) ([]Reservation, error) {
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var all []Reservation
+	if err := json.Unmarshal(data, &all); err != nil {
+		return nil, nil // corrupt state only loses short-lived holds
+	}
+	var out []Reservation
+	for _, r := range all {
+		if r.Until.After(now) {
+			out = append(out, r)
+		}
+	}
+	return out, nil
+}
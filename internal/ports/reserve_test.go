@@ -0,0 +1,39 @@
+package ports
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestReservationStoreSuggest(t *testing.T) {
+	dir := t.TempDir()
+	base := SuggestOptions{Range: PortRange{From: 4000, To: 4001}, bindable: func(uint16) bool { return true }}
+	suggest := func(project string) uint16 {
+		opts := base
+		opts.Project = project
+		// A new store each time, as a separate tapas process would have.
+		s, err := NewReservationStore(dir).Suggest(opts, time.Minute)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return s.Port
+	}
+
+	var wg sync.WaitGroup
+	got := make([]uint16, 2)
+	for i, project := range []string{"shop", "blog"} {
+		wg.Add(1)
+		go func(i int, project string) {
+			defer wg.Done()
+			got[i] = suggest(project)
+		}(i, project)
+	}
+	wg.Wait()
+	if got[0] == got[1] {
+		t.Fatalf("concurrent suggestions for two projects: both %d", got[0])
+	}
+	if again := suggest("shop"); again != got[0] {
+		t.Errorf("own reservation skipped: %d then %d", got[0], again)
+	}
+}
@@ -0,0 +1,129 @@
+package ports
+
+import "strings"
+
+// SecurityContext is the security-relevant state of a listening process (Linux: /proc/<pid>/status and attr).
+// Nil on Port when unavailable (other OS, or process owned by another user without privileges).
+type SecurityContext struct {
+	EUID       int    // effective user ID
+	CapEff     uint64 // effective capability bitmask
+	Seccomp    string // "disabled", "strict", or "filter"
+	NoNewPrivs bool
+	Label      string // SELinux context or AppArmor profile; "" when no LSM label
+	Setuid     bool   // executable has the setuid bit
+	Setgid     bool   // executable has the setgid bit
+
+	// Namespaces split by whether they match the host's (PID 1, or ours when PID 1 is unreadable).
+	SharedNamespaces   []string // e.g. "mnt", "net"
+	IsolatedNamespaces []string // e.g. "net" for a process in its own network namespace
+}
+
+// capabilityNames maps capability bit numbers to names (linux/capability.h), lowercased.
+var capabilityNames = []string{
+	"cap_chown", "cap_dac_override", "cap_dac_read_search", "cap_fowner", "cap_fsetid",
+	"cap_kill", "cap_setgid", "cap_setuid", "cap_setpcap", "cap_linux_immutable",
+	"cap_net_bind_service", "cap_net_broadcast", "cap_net_admin", "cap_net_raw", "cap_ipc_lock",
+	"cap_ipc_owner", "cap_sys_module", "cap_sys_rawio", "cap_sys_chroot", "cap_sys_ptrace",
+	"cap_sys_pacct", "cap_sys_admin", "cap_sys_boot", "cap_sys_nice", "cap_sys_resource",
+	"cap_sys_time", "cap_sys_tty_config", "cap_mknod", "cap_lease", "cap_audit_write",
+	"cap_audit_control", "cap_setfcap", "cap_mac_override", "cap_mac_admin", "cap_syslog",
+	"cap_wake_alarm", "cap_block_suspend", "cap_audit_read", "cap_perfmon", "cap_bpf",
+	"cap_checkpoint_restore",
+}
+
+// allKnownCaps is the mask of every capability we can name; a process holding all of them is "full".
+var allKnownCaps = uint64(1)<<uint(len(capabilityNames)) - 1
+
+// Capabilities returns the names of the effective capabilities (e.g. "cap_net_bind_service").
+func (s *SecurityContext) Capabilities() []string {
+	var names []string
+	for i, name := range capabilityNames {
+		if s.CapEff&(1<<uint(i)) != 0 {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// HasCapability reports whether the effective set contains name ("net_bind_service" or "cap_net_bind_service").
+func (s *SecurityContext) HasCapability(name string) bool {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if !strings.HasPrefix(name, "cap_") {
+		name = "cap_" + name
+	}
+	for i, n := range capabilityNames {
+		if n == name {
+			return s.CapEff&(1<<uint(i)) != 0
+		}
+	}
+	return false
+}
+
+// CapabilitySummary returns a short label: "none", "full (root)", or the comma-separated names.
+func (s *SecurityContext) CapabilitySummary() string {
+	if s.CapEff == 0 {
+		return "none"
+	}
+	if s.CapEff&allKnownCaps == allKnownCaps {
+		return "full (root)"
+	}
+	return strings.Join(s.Capabilities(), ", ")
+}
+
+// MatchesFilter reports whether the context satisfies a filter term such as "setuid",
+// "nonewprivs", "seccomp", "root", "cap", "isolated", "cap:net_bind_service", or "ns:net".
+func (s *SecurityContext) MatchesFilter(key, value string) bool {
+	switch key {
+	case "cap":
+		if value == "" || value == "any" {
+			return s.CapEff != 0
+		}
+		return s.HasCapability(value)
+	case "ns":
+		if value == "" {
+			return len(s.IsolatedNamespaces) > 0
+		}
+		return containsString(s.IsolatedNamespaces, value)
+	}
+	switch value {
+	case "setuid":
+		return s.Setuid || s.Setgid
+	case "nonewprivs":
+		return s.NoNewPrivs
+	case "seccomp":
+		return s.Seccomp != "" && s.Seccomp != "disabled"
+	case "root":
+		return s.EUID == 0
+	case "isolated":
+		return len(s.IsolatedNamespaces) > 0
+	case "labeled":
+		return s.Label != ""
+	}
+	return false
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// seccompModeName maps the Seccomp field of /proc/<pid>/status to a name.
+func seccompModeName(mode string) string {
+	switch strings.TrimSpace(mode) {
+	case "0":
+		return "disabled"
+	case "1":
+		return "strict"
+	case "2":
+		return "filter"
+	default:
+		return ""
+	}
+}
+
+// namespaceKinds are the /proc/<pid>/ns entries compared against the host.
+var namespaceKinds = []string{"cgroup", "ipc", "mnt", "net", "pid", "user", "uts"}
@@ -0,0 +1,89 @@
+//go:build linux
+
+package ports
+
+import (
+	"bufio"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// hostNamespaces returns namespace link targets (e.g. "net:[4026531840]") for the host.
+// Reading /proc/1/ns needs privileges; fall back to our own namespaces, which are the host's
+// unless TAPAS itself runs in a container.
+func hostNamespaces() map[string]string {
+	for _, base := range []string{"/proc/1/ns/", "/proc/self/ns/"} {
+		ns := make(map[string]string)
+		for _, kind := range namespaceKinds {
+			if target, err := os.Readlink(base + kind); err == nil {
+				ns[kind] = target
+			}
+		}
+		if len(ns) > 0 {
+			return ns
+		}
+	}
+	return nil
+}
+
+// readSecurityContext reads /proc/<pid>/status, attr/current, ns/* and the executable's mode.
+// Returns nil when status is unreadable.
+func readSecurityContext(pid int, executable string, hostNS map[string]string) *SecurityContext {
+	if pid <= 0 {
+		return nil
+	}
+	dir := "/proc/" + strconv.Itoa(pid) + "/"
+	f, err := os.Open(dir + "status")
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+	sc := &SecurityContext{EUID: -1}
+	s := bufio.NewScanner(f)
+	for s.Scan() {
+		key, value, ok := strings.Cut(s.Text(), ":")
+		if !ok {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		switch key {
+		case "Uid":
+			// real, effective, saved, filesystem
+			if fields := strings.Fields(value); len(fields) >= 2 {
+				sc.EUID, _ = strconv.Atoi(fields[1])
+			}
+		case "CapEff":
+			sc.CapEff, _ = strconv.ParseUint(value, 16, 64)
+		case "NoNewPrivs":
+			sc.NoNewPrivs = value == "1"
+		case "Seccomp":
+			sc.Seccomp = seccompModeName(value)
+		}
+	}
+	if label, err := readFile(dir + "attr/current"); err == nil {
+		label = strings.TrimRight(strings.TrimSpace(label), "\x00")
+		if label != "unconfined" && label != "" {
+			sc.Label = label
+		}
+	}
+	for _, kind := range namespaceKinds {
+		target, err := os.Readlink(dir + "ns/" + kind)
+		host, known := hostNS[kind]
+		if err != nil || !known {
+			continue
+		}
+		if target == host {
+			sc.SharedNamespaces = append(sc.SharedNamespaces, kind)
+		} else {
+			sc.IsolatedNamespaces = append(sc.IsolatedNamespaces, kind)
+		}
+	}
+	if executable != "" {
+		if info, err := os.Stat(executable); err == nil {
+			sc.Setuid = info.Mode()&os.ModeSetuid != 0
+			sc.Setgid = info.Mode()&os.ModeSetgid != 0
+		}
+	}
+	return sc
+}
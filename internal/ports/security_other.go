@@ -0,0 +1,13 @@
+//go:build !linux
+
+package ports
+
+// hostNamespaces is Linux-only; namespaces are not exposed elsewhere.
+func hostNamespaces() map[string]string {
+	return nil
+}
+
+// readSecurityContext is Linux-only (/proc); other platforms report no security context.
+func readSecurityContext(pid int, executable string, hostNS map[string]string) *SecurityContext {
+	return nil
+}
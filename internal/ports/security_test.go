@@ -0,0 +1,39 @@
+package ports
+
+import "testing"
+
+func TestSecurityContext_Capabilities(t *testing.T) {
+	sc := &SecurityContext{CapEff: 1 << 10} // cap_net_bind_service
+	if !sc.HasCapability("net_bind_service") || !sc.HasCapability("CAP_NET_BIND_SERVICE") {
+		t.Error("expected cap_net_bind_service")
+	}
+	if sc.HasCapability("sys_admin") {
+		t.Error("unexpected cap_sys_admin")
+	}
+	if got := sc.CapabilitySummary(); got != "cap_net_bind_service" {
+		t.Errorf("CapabilitySummary() = %q", got)
+	}
+	if got := (&SecurityContext{CapEff: allKnownCaps}).CapabilitySummary(); got != "full (root)" {
+		t.Errorf("CapabilitySummary() = %q, want full (root)", got)
+	}
+}
+
+func TestSecurityContext_MatchesFilter(t *testing.T) {
+	sc := &SecurityContext{EUID: 0, Seccomp: "filter", IsolatedNamespaces: []string{"net"}}
+	tests := []struct {
+		key, value string
+		want       bool
+	}{
+		{"is", "root", true},
+		{"is", "seccomp", true},
+		{"is", "setuid", false},
+		{"ns", "net", true},
+		{"ns", "pid", false},
+		{"cap", "", false},
+	}
+	for _, tt := range tests {
+		if got := sc.MatchesFilter(tt.key, tt.value); got != tt.want {
+			t.Errorf("MatchesFilter(%q, %q) = %v, want %v", tt.key, tt.value, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,28 @@
+//go:build linux
+
+package ports
+
+import (
+	"os"
+	"strconv"
+	"strings"
+)
+
+// inUserSession reports whether pid was started from a login session rather than by a service
+// manager: its cgroup is a logind session scope, or it was started through sudo.
+func inUserSession(pid int) bool {
+	if pid <= 0 {
+		return false
+	}
+	if data, err := os.ReadFile("/proc/" + strconv.Itoa(pid) + "/cgroup"); err == nil {
+		if strings.Contains(string(data), "/session-") {
+			return true
+		}
+	}
+	for _, kv := range processEnviron(pid) {
+		if strings.HasPrefix(kv, "SUDO_USER=") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,8 @@
+//go:build !linux
+
+package ports
+
+// inUserSession is only known on Linux (cgroups); elsewhere the root check never fires.
+func inUserSession(pid int) bool {
+	return false
+}
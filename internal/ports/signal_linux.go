@@ -0,0 +1,79 @@
+//go:build linux
+
+package ports
+
+import (
+	"errors"
+	"syscall"
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+// openPidfd opens a pidfd for p.PID and verifies the snapshot identity through it.
+// Once the pidfd is open it keeps referring to that process even if the PID is reused,
+// so a verified pidfd is safe to signal. Returns -1 and no error when pidfds are
+// unavailable (kernel < 5.3, or blocked by a seccomp sandbox).
+func openPidfd(p Port) (int, error) {
+	fd, err := unix.PidfdOpen(p.PID, 0)
+	if err != nil {
+		if errors.Is(err, unix.ENOSYS) || errors.Is(err, unix.EPERM) {
+			return -1, nil
+		}
+		return -1, err
+	}
+	if err := verifyIdentity(p); err != nil {
+		unix.Close(fd)
+		return -1, err
+	}
+	return fd, nil
+}
+
+// signalVerified sends sig via pidfd_send_signal, falling back to a checked kill(2).
+func signalVerified(p Port, sig syscall.Signal) error {
+	fd, err := openPidfd(p)
+	if err != nil {
+		return err
+	}
+	if fd < 0 {
+		if err := verifyIdentity(p); err != nil {
+			return err
+		}
+		return syscall.Kill(p.PID, sig)
+	}
+	defer unix.Close(fd)
+	return unix.PidfdSendSignal(fd, sig, nil, 0)
+}
+
+// WaitExit waits up to timeout for the process in the snapshot to exit.
+// Returns true when it is gone (or the PID now belongs to another process).
+// On Linux this polls the pidfd, which becomes readable when the process exits.
+func WaitExit(p Port, timeout time.Duration) bool {
+	if p.PID <= 0 {
+		return true
+	}
+	fd, err := openPidfd(p)
+	if err != nil {
+		return errors.Is(err, syscall.ESRCH) || errors.Is(err, errIdentityChanged)
+	}
+	if fd < 0 {
+		return pollExit(p, timeout)
+	}
+	defer unix.Close(fd)
+	deadline := time.Now().Add(timeout)
+	for {
+		remaining := time.Until(deadline)
+		if remaining < 0 {
+			remaining = 0
+		}
+		fds := []unix.PollFd{{Fd: int32(fd), Events: unix.POLLIN}}
+		n, err := unix.Poll(fds, int(remaining/time.Millisecond))
+		if err == unix.EINTR {
+			continue
+		}
+		if err != nil {
+			return pollExit(p, time.Until(deadline))
+		}
+		return n > 0
+	}
+}
@@ -0,0 +1,27 @@
+//go:build !linux
+
+package ports
+
+import (
+	"syscall"
+	"time"
+)
+
+// signalVerified checks the snapshot identity, then sends sig with kill(2).
+// Without pidfds there is a small window between check and signal; it is milliseconds
+// instead of the seconds a confirmation modal may stay open.
+func signalVerified(p Port, sig syscall.Signal) error {
+	if err := verifyIdentity(p); err != nil {
+		return err
+	}
+	return syscall.Kill(p.PID, sig)
+}
+
+// WaitExit waits up to timeout for the process in the snapshot to exit.
+// Returns true when it is gone (or the PID now belongs to another process).
+func WaitExit(p Port, timeout time.Duration) bool {
+	if p.PID <= 0 {
+		return true
+	}
+	return pollExit(p, timeout)
+}
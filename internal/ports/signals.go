@@ -0,0 +1,65 @@
+package ports
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+// SignalInfo describes a signal offered in the signal menu.
+type SignalInfo struct {
+	Signal     syscall.Signal
+	Name       string // e.g. "SIGHUP"
+	Hint       string // what it is typically used for
+	Terminates bool   // expected to end the process; callers wait for exit and port release
+}
+
+// Signals is the signal menu, in display order: graceful stops first, then control signals, SIGKILL last.
+var Signals = []SignalInfo{
+	{syscall.SIGTERM, "SIGTERM", "graceful stop", true},
+	{syscall.SIGINT, "SIGINT", "Ctrl-C: clean shutdown (Node, Python)", true},
+	{syscall.SIGHUP, "SIGHUP", "reload config (nginx, gunicorn)", false},
+	{syscall.SIGUSR1, "SIGUSR1", "app-defined (Node inspector, puma)", false},
+	{syscall.SIGUSR2, "SIGUSR2", "app-defined (puma phased restart)", false},
+	{syscall.SIGSTOP, "SIGSTOP", "pause the process", false},
+	{syscall.SIGCONT, "SIGCONT", "resume a paused process", false},
+	{syscall.SIGKILL, "SIGKILL", "force kill (cannot be caught)", true},
+}
+
+// LookupSignal returns the menu entry for sig, or a generic entry for signals not in the menu.
+func LookupSignal(sig syscall.Signal) SignalInfo {
+	for _, info := range Signals {
+		if info.Signal == sig {
+			return info
+		}
+	}
+	return SignalInfo{Signal: sig, Name: fmt.Sprintf("signal %d", int(sig))}
+}
+
+// ParseSignal accepts "HUP", "SIGHUP", "sighup" or a number such as "1".
+func ParseSignal(s string) (syscall.Signal, error) {
+	s = strings.ToUpper(strings.TrimSpace(s))
+	if n, err := strconv.Atoi(s); err == nil && n > 0 {
+		return syscall.Signal(n), nil
+	}
+	if !strings.HasPrefix(s, "SIG") {
+		s = "SIG" + s
+	}
+	for _, info := range Signals {
+		if info.Name == s {
+			return info.Signal, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown signal %q", s)
+}
+
+// SendSignal sends a signal that is not expected to end the process (SIGHUP, SIGSTOP, ...).
+// Like KillPort, errors are prefixed with the port for display.
+func SendSignal(p Port, sig syscall.Signal) KillResult {
+	r := Signal(p, sig)
+	if !r.OK && r.Error != "" {
+		r.Error = fmt.Sprintf("Failed to send %s to port %d (%s)", LookupSignal(sig).Name, p.PortNum, r.Error)
+	}
+	return r
+}
@@ -0,0 +1,56 @@
+package ports
+
+import "strings"
+
+// StaleReason explains why a listener looks abandoned (nobody owns it anymore).
+type StaleReason string
+
+const (
+	// StaleOrphaned: a dev server whose launcher is gone, so it was reparented to PID 1.
+	StaleOrphaned StaleReason = "orphaned"
+	// StaleCwdDeleted: the working directory was removed (e.g. a deleted git worktree).
+	StaleCwdDeleted StaleReason = "cwd-deleted"
+	// StaleExeReplaced: the executable on disk was deleted or replaced by a rebuild.
+	StaleExeReplaced StaleReason = "exe-replaced"
+)
+
+// Description returns a one-line, human-readable explanation for the details view.
+func (r StaleReason) Description() string {
+	switch r {
+	case StaleOrphaned:
+		return "orphaned: parent is PID 1 (launcher exited)"
+	case StaleCwdDeleted:
+		return "working directory was deleted"
+	case StaleExeReplaced:
+		return "executable was deleted or replaced since start"
+	default:
+		return string(r)
+	}
+}
+
+// deletedSuffix is what Linux appends to /proc/<pid>/cwd and /proc/<pid>/exe links whose target is gone.
+const deletedSuffix = " (deleted)"
+
+// trimDeleted strips the " (deleted)" suffix from a /proc link target and reports whether it was present.
+func trimDeleted(path string) (string, bool) {
+	if strings.HasSuffix(path, deletedSuffix) {
+		return strings.TrimSuffix(path, deletedSuffix), true
+	}
+	return path, false
+}
+
+// DetectStale fills StaleReasons from PPID and the cwd/exe flags collected by the lister.
+// Only dev servers (Framework or Environment set) count as orphaned: system daemons are
+// normally children of PID 1 and flagging them would be noise.
+func DetectStale(p *Port, cwdDeleted, exeDeleted bool) {
+	p.StaleReasons = nil
+	if p.PPID == 1 && (p.Framework != "" || p.Environment != "") {
+		p.StaleReasons = append(p.StaleReasons, StaleOrphaned)
+	}
+	if cwdDeleted {
+		p.StaleReasons = append(p.StaleReasons, StaleCwdDeleted)
+	}
+	if exeDeleted {
+		p.StaleReasons = append(p.StaleReasons, StaleExeReplaced)
+	}
+}
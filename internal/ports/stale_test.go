@@ -0,0 +1,42 @@
+package ports
+
+import "testing"
+
+func TestTrimDeleted(t *testing.T) {
+	got, deleted := trimDeleted("/home/user/wt/feature (deleted)")
+	if got != "/home/user/wt/feature" || !deleted {
+		t.Errorf("trimDeleted = (%q, %v), want (%q, true)", got, deleted, "/home/user/wt/feature")
+	}
+	got, deleted = trimDeleted("/home/user/app")
+	if got != "/home/user/app" || deleted {
+		t.Errorf("trimDeleted = (%q, %v), want unchanged", got, deleted)
+	}
+}
+
+func TestDetectStale(t *testing.T) {
+	tests := []struct {
+		name       string
+		p          Port
+		cwdDeleted bool
+		exeDeleted bool
+		want       []StaleReason
+	}{
+		{"healthy", Port{PPID: 400, Framework: "Vite"}, false, false, nil},
+		{"daemon under init is not orphaned", Port{PPID: 1, Process: "sshd"}, false, false, nil},
+		{"orphaned dev server", Port{PPID: 1, Environment: "npm"}, false, false, []StaleReason{StaleOrphaned}},
+		{"deleted worktree and rebuilt binary", Port{PPID: 400}, true, true, []StaleReason{StaleCwdDeleted, StaleExeReplaced}},
+	}
+	for _, tt := range tests {
+		p := tt.p
+		DetectStale(&p, tt.cwdDeleted, tt.exeDeleted)
+		if len(p.StaleReasons) != len(tt.want) {
+			t.Errorf("%s: StaleReasons = %v, want %v", tt.name, p.StaleReasons, tt.want)
+			continue
+		}
+		for i := range tt.want {
+			if p.StaleReasons[i] != tt.want[i] {
+				t.Errorf("%s: StaleReasons = %v, want %v", tt.name, p.StaleReasons, tt.want)
+			}
+		}
+	}
+}
@@ -0,0 +1,201 @@
+package ports
+
+import (
+	"fmt"
+	"hash/fnv"
+	"net"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// DefaultSuggestRange is where tapas port suggest looks when no range is configured.
+var DefaultSuggestRange = PortRange{From: 3000, To: 9999}
+
+// ianaEphemeralRange is the IANA dynamic range, used when the kernel's range is unknown.
+var ianaEphemeralRange = PortRange{From: 49152, To: 65535}
+
+// PortRange is an inclusive range of ports.
+type PortRange struct {
+	From, To uint16
+}
+
+// ParsePortRange parses "3000-9999" or a single port "5432".
+func ParsePortRange(s string) (PortRange, error) {
+	lo, hi, found := strings.Cut(strings.TrimSpace(s), "-")
+	from, err := strconv.ParseUint(strings.TrimSpace(lo), 10, 16)
+	if err != nil || from == 0 {
+		return PortRange{}, fmt.Errorf("invalid port range %q", s)
+	}
+	to := from
+	if found {
+		if to, err = strconv.ParseUint(strings.TrimSpace(hi), 10, 16); err != nil || to < from {
+			return PortRange{}, fmt.Errorf("invalid port range %q", s)
+		}
+	}
+	return PortRange{From: uint16(from), To: uint16(to)}, nil
+}
+
+// Contains reports whether port is in the range.
+func (r PortRange) Contains(port uint16) bool {
+	return port >= r.From && port <= r.To
+}
+
+func (r PortRange) String() string {
+	if r.From == r.To {
+		return strconv.Itoa(int(r.From))
+	}
+	return fmt.Sprintf("%d-%d", r.From, r.To)
+}
+
+// commonDevPorts are framework defaults; a new service that takes one collides with the next
+// "npm run dev" anywhere on the machine.
+var commonDevPorts = map[uint16]bool{
+	3000: true, 3001: true, 4200: true, 5000: true, 5001: true, 5173: true, 5174: true,
+	8000: true, 8080: true, 8081: true, 8443: true, 8888: true, 9000: true,
+}
+
+// SuggestOptions are the inputs of SuggestPort.
+type SuggestOptions struct {
+	Project   string            // the preferred port is derived from this name
+	Range     PortRange         // candidates; DefaultSuggestRange when zero
+	Repos     []string          // directories whose declared ports are never suggested
+	Own       string            // the project's own repo: its declared ports stay candidates
+	Avoid     []PortRange       // configured ports that are never suggested
+	Listening []Port            // current listeners
+	Held      map[uint16]string // ports reserved by other projects (see ReservationStore)
+
+	bindable func(uint16) bool // tests replace the bind check
+}
+
+// Suggestion is the chosen port and why the ports before it were passed over.
+type Suggestion struct {
+	Port      uint16
+	Preferred uint16   // the project's hash-based first choice
+	Skipped   []string // e.g. "4711: in use by node (PID 12)"
+}
+
+// SuggestPort returns a port for opts.Project that is free now, outside the kernel's ephemeral
+// range, not a well-known or framework default port, and not declared by another repo. Probing
+// starts at a hash of the project name, so a project gets the same port every time it is still
+// available, including after the port was written to its own .env.
+func SuggestPort(opts SuggestOptions) (Suggestion, error) {
+	r := opts.Range
+	if r == (PortRange{}) {
+		r = DefaultSuggestRange
+	}
+	if opts.bindable == nil {
+		opts.bindable = portBindable
+	}
+	eph := EphemeralRange()
+	inUse := make(map[uint16]string)
+	for _, p := range opts.Listening {
+		inUse[p.PortNum] = ProcessLabel(&p)
+	}
+	declared := make(map[uint16]string)
+	for _, dir := range opts.Repos {
+		if opts.Own != "" && filepath.Clean(dir) == filepath.Clean(opts.Own) {
+			continue
+		}
+		for _, port := range DeclaredPorts(dir) {
+			declared[port] = dir
+		}
+	}
+
+	size := int(r.To) - int(r.From) + 1
+	h := fnv.New32a()
+	h.Write([]byte(strings.ToLower(opts.Project)))
+	start := int(h.Sum32() % uint32(size))
+	s := Suggestion{Preferred: r.From + uint16(start)}
+	for i := 0; i < size; i++ {
+		port := r.From + uint16((start+i)%size)
+		reason := ""
+		switch {
+		case eph.Contains(port):
+			reason = "in the ephemeral range " + eph.String()
+		case appByPort(port) != "" || commonDevPorts[port]:
+			reason = "well-known or framework default port"
+		case inRanges(port, opts.Avoid):
+			reason = "reserved in config"
+		case declared[port] != "":
+			reason = "declared in " + declared[port]
+		case opts.Held[port] != "":
+			reason = "reserved for " + opts.Held[port]
+		case inUse[port] != "":
+			reason = "in use by " + inUse[port]
+		case !opts.bindable(port):
+			reason = "in use"
+		}
+		if reason == "" {
+			s.Port = port
+			return s, nil
+		}
+		if len(s.Skipped) < 20 { // the first few explain a moved suggestion; the rest is noise
+			s.Skipped = append(s.Skipped, fmt.Sprintf("%d: %s", port, reason))
+		}
+	}
+	return s, fmt.Errorf("no free port in %s", r)
+}
+
+func inRanges(port uint16, ranges []PortRange) bool {
+	for _, r := range ranges {
+		if r.Contains(port) {
+			return true
+		}
+	}
+	return false
+}
+
+// portBindable tries to listen on port on all interfaces; listeners of other users that the
+// lister cannot see still make it fail.
+func portBindable(port uint16) bool {
+	l, err := net.Listen("tcp", ":"+strconv.Itoa(int(port)))
+	if err != nil {
+		return false
+	}
+	l.Close()
+	return true
+}
+
+// declaredPortFiles are the files in a repo root that DeclaredPorts reads, with the pattern
+// whose first group is a port.
+var declaredPortFiles = []struct {
+	names   []string
+	pattern *regexp.Regexp
+}{
+	// PORT=3000, API_PORT="4000", export DB_PORT=5433
+	{[]string{".env", ".env.example", ".env.local", ".env.development", ".env.test"},
+		regexp.MustCompile(`(?m)^\s*(?:export\s+)?[A-Za-z0-9_]*PORT\s*=\s*["']?(\d{2,5})\b`)},
+	// - "3000:3000", - 127.0.0.1:5433:5432, - 8080-8081:80
+	{[]string{"docker-compose.yml", "docker-compose.yaml", "compose.yml", "compose.yaml"},
+		regexp.MustCompile(`(?m)^\s*-\s*["']?(?:[0-9.]+:)?(\d{2,5})(?:-\d+)?:\d+`)},
+	// "dev": "vite --port 5180", "start": "PORT=4000 node server.js"
+	{[]string{"package.json"},
+		regexp.MustCompile(`(?:--port[= ]|\s-p\s|PORT=)(\d{2,5})\b`)},
+}
+
+// DeclaredPorts returns the ports a repo claims in its .env files, compose files and package.json
+// scripts. Only the repo root is read; unreadable files are skipped.
+func DeclaredPorts(dir string) []uint16 {
+	var out []uint16
+	seen := make(map[uint16]bool)
+	for _, f := range declaredPortFiles {
+		for _, name := range f.names {
+			data, err := os.ReadFile(filepath.Join(dir, name))
+			if err != nil {
+				continue
+			}
+			for _, m := range f.pattern.FindAllStringSubmatch(string(data), -1) {
+				n, err := strconv.ParseUint(m[1], 10, 16)
+				if err != nil || n == 0 || seen[uint16(n)] {
+					continue
+				}
+				seen[uint16(n)] = true
+				out = append(out, uint16(n))
+			}
+		}
+	}
+	return out
+}
@@ -0,0 +1,89 @@
+package ports
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDeclaredPorts(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		".env":               "PORT=4100\nexport API_PORT=\"4200\"\nHOST=localhost\n",
+		"docker-compose.yml": "services:\n  db:\n    ports:\n      - \"5433:5432\"\n      - 127.0.0.1:6380:6379\n",
+		"package.json":       `{"scripts": {"dev": "vite --port 5180", "start": "PORT=4100 node server.js"}}`,
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	got := make(map[uint16]bool)
+	for _, p := range DeclaredPorts(dir) {
+		got[p] = true
+	}
+	for _, want := range []uint16{4100, 4200, 5433, 6380, 5180} {
+		if !got[want] {
+			t.Errorf("DeclaredPorts missing %d (got %v)", want, got)
+		}
+	}
+	if got[5432] || got[6379] {
+		t.Errorf("DeclaredPorts returned container-side ports: %v", got)
+	}
+}
+
+func TestSuggestPortStableAndAvoiding(t *testing.T) {
+	all := func(uint16) bool { return true }
+	opts := SuggestOptions{Project: "shop", Range: PortRange{From: 4000, To: 4999}, bindable: all}
+	first, err := SuggestPort(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again, _ := SuggestPort(opts); again.Port != first.Port {
+		t.Fatalf("suggestion not stable: %d then %d", first.Port, again.Port)
+	}
+	if first.Port != first.Preferred {
+		t.Fatalf("free range: got %d, preferred %d", first.Port, first.Preferred)
+	}
+
+	opts.Listening = []Port{{PID: 1, PortNum: first.Port, Process: "node"}}
+	opts.Held = map[uint16]string{first.Port + 1: "blog"}
+	moved, err := SuggestPort(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if moved.Port == first.Port || moved.Port == first.Port+1 || len(moved.Skipped) != 2 {
+		t.Errorf("in use and held ports not skipped: got %d, skipped %v", moved.Port, moved.Skipped)
+	}
+
+	opts = SuggestOptions{Project: "shop", Range: PortRange{From: 4000, To: 4001}, Avoid: []PortRange{{4000, 4001}}, bindable: all}
+	if _, err := SuggestPort(opts); err == nil {
+		t.Error("exhausted range: expected an error")
+	}
+}
+
+func TestSuggestPortOwnDeclaredPort(t *testing.T) {
+	own, other := t.TempDir(), t.TempDir()
+	opts := SuggestOptions{Project: "shop", Range: PortRange{From: 4000, To: 4999}, Own: own,
+		Repos: []string{own, other}, bindable: func(uint16) bool { return true }}
+	first, err := SuggestPort(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// tapas port suggest --env PORT > .env, then the next run.
+	env := []byte(fmt.Sprintf("PORT=%d\n", first.Port))
+	if err := os.WriteFile(filepath.Join(own, ".env"), env, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if again, _ := SuggestPort(opts); again.Port != first.Port {
+		t.Errorf("own .env port skipped: %d then %d (%v)", first.Port, again.Port, again.Skipped)
+	}
+	// Another repo declaring it still moves the suggestion.
+	if err := os.WriteFile(filepath.Join(other, ".env"), env, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if moved, _ := SuggestPort(opts); moved.Port == first.Port {
+		t.Errorf("port declared by another repo suggested: %d", moved.Port)
+	}
+}
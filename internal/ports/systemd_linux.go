@@ -0,0 +1,35 @@
+//go:build linux
+
+package ports
+
+import (
+	"os"
+	"strconv"
+	"strings"
+)
+
+// systemdUnit returns the systemd service managing pid ("sshd.service"), from its cgroup path
+// (e.g. 0::/system.slice/sshd.service). Empty for processes not started by a service unit.
+func systemdUnit(pid int) string {
+	if pid <= 0 {
+		return ""
+	}
+	data, err := os.ReadFile("/proc/" + strconv.Itoa(pid) + "/cgroup")
+	if err != nil {
+		return ""
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		parts := strings.SplitN(line, ":", 3)
+		if len(parts) != 3 || (parts[1] != "" && parts[1] != "name=systemd") {
+			continue
+		}
+		segs := strings.Split(parts[2], "/")
+		// The innermost .service wins: user@1000.service/app.slice/dev.service is dev.service.
+		for i := len(segs) - 1; i >= 0; i-- {
+			if strings.HasSuffix(segs[i], ".service") && !strings.HasPrefix(segs[i], "user@") {
+				return segs[i]
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,213 @@
+package ports
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// DefaultGracePeriod is how long Terminate waits for exit and port release before giving up or escalating.
+const DefaultGracePeriod = 5 * time.Second
+
+// portReleasePollInterval is how often Terminate re-checks whether the port is still bound.
+const portReleasePollInterval = 100 * time.Millisecond
+
+// TerminateOptions controls a graceful kill.
+type TerminateOptions struct {
+	Signal   syscall.Signal // first signal to send; 0 means SIGTERM
+	Grace    time.Duration  // wait for exit and port release; 0 means DefaultGracePeriod
+	Escalate bool           // send SIGKILL automatically when a process outlives Grace
+}
+
+// TerminateResult reports what actually happened, not just whether a signal was delivered.
+type TerminateResult struct {
+	KillResult
+	Signal    syscall.Signal // the signal sent first
+	Processes int            // processes signalled
+	Exited    bool           // every signalled process is gone
+	Escalated bool           // SIGKILL was sent after the grace period
+	Ports     []uint16       // ports checked for release
+	PortFree  bool           // nothing listens on any of Ports anymore
+	Elapsed   time.Duration  // from first signal until exit (or until we stopped waiting)
+}
+
+// Terminate signals the process in the snapshot, waits for it to exit and for its port to be
+// released, and optionally escalates to SIGKILL. It blocks for up to about twice the grace
+// period; run it off the UI goroutine.
+func Terminate(p Port, opts TerminateOptions) TerminateResult {
+	return terminateAll([]Port{p}, []uint16{p.PortNum}, opts)
+}
+
+// Terminate signals every target in plan order (children first), then waits for all of them
+// to exit and for all affected ports to be released (see Terminate).
+func (plan KillPlan) Terminate(opts TerminateOptions) TerminateResult {
+	return terminateAll(plan.snapshots(), plan.AffectedPorts(), opts)
+}
+
+// Signal sends a non-terminating signal (SIGHUP, SIGSTOP, ...) to every target in plan order.
+func (plan KillPlan) Signal(sig syscall.Signal) KillResult {
+	sent, err := signalEach(plan.snapshots(), sig)
+	if err != nil && sent == 0 {
+		return killError(err)
+	}
+	if err != nil {
+		return KillResult{OK: false, Error: fmt.Sprintf("signalled %d of %d processes (%s)", sent, len(plan.Targets), signalErrorMessage(err))}
+	}
+	return KillResult{OK: true}
+}
+
+// signalEach signals targets in order. A target that already exited is not an error when
+// others were signalled (killing a child often takes its parent down, and vice versa).
+func signalEach(targets []Port, sig syscall.Signal) (sent int, firstErr error) {
+	for _, t := range targets {
+		err := sendSignal(t, sig)
+		switch {
+		case err == nil:
+			sent++
+		case errors.Is(err, syscall.ESRCH) && len(targets) > 1:
+		default:
+			if firstErr == nil {
+				firstErr = err
+			}
+		}
+	}
+	return sent, firstErr
+}
+
+func terminateAll(targets []Port, portNums []uint16, opts TerminateOptions) TerminateResult {
+	sig := opts.Signal
+	if sig == 0 {
+		sig = syscall.SIGTERM
+	}
+	grace := opts.Grace
+	if grace <= 0 {
+		grace = DefaultGracePeriod
+	}
+	for _, t := range targets {
+		if t.PID <= 0 {
+			return TerminateResult{KillResult: KillResult{OK: false, Error: "invalid pid"}}
+		}
+	}
+	start := time.Now()
+	r := TerminateResult{KillResult: KillResult{OK: true}, Signal: sig, Ports: portNums}
+	sent, err := signalEach(targets, sig)
+	r.Processes = sent
+	if sent == 0 || (err != nil && len(targets) == 1) {
+		if err == nil {
+			err = syscall.ESRCH
+		}
+		r.KillResult = killError(err)
+		return r
+	}
+	r.Exited = waitAllExit(targets, start.Add(grace))
+	if !r.Exited && opts.Escalate && sig != syscall.SIGKILL {
+		if n, _ := signalEach(targets, syscall.SIGKILL); n > 0 {
+			r.Escalated = true
+			r.Exited = waitAllExit(targets, time.Now().Add(grace))
+		}
+	}
+	r.Elapsed = time.Since(start)
+	if r.Exited {
+		r.PortFree = waitPortsFree(portNums, time.Now().Add(grace))
+	}
+	return r
+}
+
+// waitAllExit waits for every target until deadline; processes are independent, so waiting
+// for them one after another costs no more than the slowest one.
+func waitAllExit(targets []Port, deadline time.Time) bool {
+	for _, t := range targets {
+		remaining := time.Until(deadline)
+		if remaining < 0 {
+			remaining = 0
+		}
+		if !WaitExit(t, remaining) {
+			return false
+		}
+	}
+	return true
+}
+
+// WaitPortsFree polls until nothing listens on any of ports, for up to timeout.
+func WaitPortsFree(ports []uint16, timeout time.Duration) bool {
+	return waitPortsFree(ports, time.Now().Add(timeout))
+}
+
+// waitPortsFree polls until nothing listens on any of ports or the deadline passes.
+// A port stays bound after exit when a child or sibling process inherited the socket.
+func waitPortsFree(ports []uint16, deadline time.Time) bool {
+	for {
+		free := true
+		for _, port := range ports {
+			if port == 0 {
+				continue
+			}
+			listening, err := PortListening(port)
+			if err != nil || listening {
+				free = false
+				break
+			}
+		}
+		if free {
+			return true
+		}
+		if time.Now().After(deadline) {
+			return false
+		}
+		time.Sleep(portReleasePollInterval)
+	}
+}
+
+// Summary describes the outcome, e.g. "exited after 1.2s, port 3000 free" or
+// "3 processes exited after 0.8s, ports 3000, 3001 free".
+func (r TerminateResult) Summary() string {
+	if !r.OK {
+		return r.Error
+	}
+	elapsed := formatSeconds(r.Elapsed)
+	subject := ""
+	if r.Processes > 1 {
+		subject = fmt.Sprintf("%d processes ", r.Processes)
+	}
+	if !r.Exited {
+		return fmt.Sprintf("%sstill running after %s", subject, elapsed)
+	}
+	s := fmt.Sprintf("%sexited after %s", subject, elapsed)
+	if r.Escalated {
+		s = fmt.Sprintf("%signored %s; SIGKILL sent, exited after %s", subject, LookupSignal(r.Signal).Name, elapsed)
+	}
+	ports := portList(r.Ports)
+	if ports == "" {
+		return s
+	}
+	if r.PortFree {
+		return s + ", " + ports + " free"
+	}
+	if len(r.Ports) > 1 {
+		return s + ", but " + ports + " are still bound"
+	}
+	return s + ", but " + ports + " is still bound"
+}
+
+// portList formats ports as "port 3000" or "ports 3000, 3001".
+func portList(ports []uint16) string {
+	var parts []string
+	for _, p := range ports {
+		if p > 0 {
+			parts = append(parts, fmt.Sprintf("%d", p))
+		}
+	}
+	switch len(parts) {
+	case 0:
+		return ""
+	case 1:
+		return "port " + parts[0]
+	}
+	return "ports " + strings.Join(parts, ", ")
+}
+
+func formatSeconds(d time.Duration) string {
+	return fmt.Sprintf("%.1fs", d.Seconds())
+}
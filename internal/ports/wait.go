@@ -0,0 +1,37 @@
+package ports
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// ListenerSpec describes the listener tapas wait expects on a port. Empty fields match anything.
+type ListenerSpec struct {
+	Process   string // glob against the process name or executable base name, case-insensitive
+	Container string // glob against the Docker container name
+	Bind      string // bind address ("127.0.0.1", "::"), or LOCAL / PUBLIC
+}
+
+// Match reports whether p is the expected listener.
+func (s ListenerSpec) Match(p Port) bool {
+	if s.Process != "" {
+		names := []string{strings.ToLower(p.Process)}
+		if p.Executable != "" {
+			names = append(names, strings.ToLower(filepath.Base(p.Executable)))
+		}
+		matched := false
+		for _, name := range names {
+			matched = matched || globMatch(s.Process, name)
+		}
+		if !matched {
+			return false
+		}
+	}
+	if s.Container != "" && !globMatch(s.Container, strings.ToLower(p.DockerContainerName)) {
+		return false
+	}
+	if s.Bind != "" && !strings.EqualFold(s.Bind, p.BindAddress) && !strings.EqualFold(s.Bind, BindLabel(p.BindAddress)) {
+		return false
+	}
+	return true
+}
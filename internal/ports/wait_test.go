@@ -0,0 +1,30 @@
+package ports
+
+import "testing"
+
+func TestListenerSpecMatch(t *testing.T) {
+	pg := Port{PID: 10, PortNum: 5432, Process: "postgres", Executable: "/usr/lib/postgresql/16/bin/postgres", BindAddress: "127.0.0.1"}
+	db := Port{PID: 20, PortNum: 5432, Process: "docker-proxy", DockerContainerName: "shop-db-1", BindAddress: "0.0.0.0"}
+
+	tests := []struct {
+		name string
+		s    ListenerSpec
+		p    Port
+		want bool
+	}{
+		{"anything", ListenerSpec{}, pg, true},
+		{"process", ListenerSpec{Process: "Postgres"}, pg, true},
+		{"process glob", ListenerSpec{Process: "post*"}, pg, true},
+		{"other process", ListenerSpec{Process: "redis-server"}, pg, false},
+		{"container glob", ListenerSpec{Container: "shop-db-*"}, db, true},
+		{"container required", ListenerSpec{Container: "shop-db-*"}, pg, false},
+		{"bind address", ListenerSpec{Bind: "127.0.0.1"}, pg, true},
+		{"bind label", ListenerSpec{Bind: "local"}, pg, true},
+		{"public is not local", ListenerSpec{Bind: "local"}, db, false},
+	}
+	for _, tt := range tests {
+		if got := tt.s.Match(tt.p); got != tt.want {
+			t.Errorf("%s: Match = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,178 @@
+package ports
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Explanation is everything tapas why knows about one listener: the TUI row and details,
+// plus where the process came from, who is connected, and how to stop it properly.
+type Explanation struct {
+	Record
+	GitBranch   string       `json:"git_branch,omitempty"`
+	PGID        int          `json:"pgid,omitempty"`
+	Ancestry    []Ancestor   `json:"ancestry"`           // parent first, up to PID 1
+	Launcher    *Ancestor    `json:"launcher,omitempty"` // npm, turbo, nodemon, ...: it may restart the server
+	Supervisor  string       `json:"supervisor,omitempty"`
+	SystemdUnit string       `json:"systemd_unit,omitempty"`
+	UserUnit    bool         `json:"user_unit,omitempty"` // managed by the user's systemd instance
+	Peers       []Peer       `json:"peers"`
+	Policy      string       `json:"policy,omitempty"` // why TAPAS would refuse or ask before killing it
+	Fix         *Remediation `json:"fix,omitempty"`    // how to stop exposing it publicly
+	Stop        StopAdvice   `json:"stop"`
+}
+
+// Ancestor is one process above the listener.
+type Ancestor struct {
+	PID  int    `json:"pid"`
+	Name string `json:"name"`
+}
+
+// Peer is one established connection to the listener.
+type Peer struct {
+	Address string `json:"address"`           // remote address:port
+	Process string `json:"process,omitempty"` // client process, when it runs on this machine and is visible
+	PID     int    `json:"pid,omitempty"`
+}
+
+// StopAdvice is the recommended way to stop the listener for good.
+type StopAdvice struct {
+	How     string `json:"how"`
+	Command string `json:"command"`
+}
+
+// supervisorNames are process managers that restart what they run; killing the child is undone.
+var supervisorNames = []struct{ prefix, name, command string }{
+	{"pm2", "PM2", "pm2 stop <app>  (see pm2 list)"},
+	{"supervisord", "supervisord", "supervisorctl stop <program>  (see supervisorctl status)"},
+	{"runsv", "runit", "sv stop <service>"},
+	{"s6-supervise", "s6", "s6-svc -d <service dir>"},
+	{"forever", "forever", "forever stop <script>  (see forever list)"},
+}
+
+// Explain gathers the story of p. Missing pieces (another user's process, no git repo) are left empty.
+func Explain(p Port) Explanation {
+	e := Explanation{Record: NewRecord(p), Ancestry: []Ancestor{}, Peers: []Peer{}, SystemdUnit: p.SystemdUnit}
+	e.GitBranch = gitBranch(p.WorkingDir)
+	if p.SystemdUnit != "" {
+		e.UserUnit = systemdUserUnit(p.PID)
+	}
+	if procs, err := listProcesses(); err == nil && p.PID > 0 {
+		byPID := make(map[int]ProcessInfo, len(procs))
+		for _, pi := range procs {
+			byPID[pi.PID] = pi
+		}
+		self, ok := byPID[p.PID]
+		if ok {
+			e.PGID = self.PGID
+			for pid, i := self.PPID, 0; pid > 0 && i < 64; i++ {
+				parent, ok := byPID[pid]
+				if !ok {
+					break
+				}
+				e.Ancestry = append(e.Ancestry, Ancestor{PID: parent.PID, Name: parent.Name})
+				if e.Supervisor == "" {
+					e.Supervisor = supervisorOf(parent.Name)
+				}
+				pid = parent.PPID
+			}
+			if root := launcherOf(self, byPID); root.PID != self.PID {
+				e.Launcher = &Ancestor{PID: root.PID, Name: root.Name}
+				if root.PGID != self.PGID {
+					e.PGID = 0 // the group does not cover the launcher; advise killing the launcher itself
+				}
+			}
+		}
+	}
+	if peers := connectedPeers(p.PortNum); peers != nil {
+		e.Peers = peers
+	}
+	if v := CheckPolicy(p); v.Decision != Allow {
+		e.Policy = v.Reason
+	}
+	e.Fix = Remediate(p)
+	e.Stop = e.stopAdvice(p)
+	return e
+}
+
+func supervisorOf(name string) string {
+	lower := strings.ToLower(name)
+	for _, s := range supervisorNames {
+		if strings.HasPrefix(lower, s.prefix) {
+			return s.name
+		}
+	}
+	return ""
+}
+
+// stopAdvice picks the owner that would otherwise bring the listener back: Docker, systemd,
+// a supervisor, a launcher; only a plain process is best stopped by signalling it directly.
+func (e Explanation) stopAdvice(p Port) StopAdvice {
+	switch {
+	case p.DockerContainerName != "":
+		return StopAdvice{"stop the container; Docker owns the port", "docker stop " + p.DockerContainerName}
+	case p.InDocker:
+		return StopAdvice{"stop the container running the process", "docker ps, then docker stop <container>"}
+	case e.SystemdUnit != "" && e.UserUnit:
+		return StopAdvice{"stop the user service; systemd may restart a killed process", "systemctl --user stop " + e.SystemdUnit}
+	case e.SystemdUnit != "":
+		return StopAdvice{"stop the service; systemd may restart a killed process", "sudo systemctl stop " + e.SystemdUnit}
+	case e.Supervisor != "":
+		for _, s := range supervisorNames {
+			if s.name == e.Supervisor {
+				return StopAdvice{"stop it through " + s.name + ", which restarts what it supervises", s.command}
+			}
+		}
+	case e.Launcher != nil && e.PGID > 0:
+		return StopAdvice{fmt.Sprintf("kill the process group of %s, which may restart the server", e.Launcher.Name),
+			fmt.Sprintf("kill -TERM -- -%d", e.PGID)}
+	case e.Launcher != nil:
+		return StopAdvice{fmt.Sprintf("kill the launcher %s and what it started", e.Launcher.Name),
+			fmt.Sprintf("kill -TERM %d  (or the TUI kill dialog with scope \"entire tree\")", e.Launcher.PID)}
+	}
+	if p.PID <= 0 {
+		return StopAdvice{"the owning process is not visible; retry with sudo to see it", fmt.Sprintf("sudo tapas why %d", p.PortNum)}
+	}
+	return StopAdvice{"plain process: terminate it", fmt.Sprintf("tapas kill %d", p.PortNum)}
+}
+
+// gitBranch returns the checked-out branch of the repo containing dir (or the short commit when
+// detached), reading .git/HEAD directly. Worktrees and submodules (.git files) are followed.
+func gitBranch(dir string) string {
+	for d := dir; d != "" && d != "/" && d != "."; d = filepath.Dir(d) {
+		gitPath := filepath.Join(d, ".git")
+		info, err := os.Stat(gitPath)
+		if err != nil {
+			continue
+		}
+		if !info.IsDir() {
+			data, err := os.ReadFile(gitPath)
+			if err != nil {
+				return ""
+			}
+			target, ok := strings.CutPrefix(strings.TrimSpace(string(data)), "gitdir:")
+			if !ok {
+				return ""
+			}
+			gitPath = strings.TrimSpace(target)
+			if !filepath.IsAbs(gitPath) {
+				gitPath = filepath.Join(d, gitPath)
+			}
+		}
+		head, err := os.ReadFile(filepath.Join(gitPath, "HEAD"))
+		if err != nil {
+			return ""
+		}
+		ref := strings.TrimSpace(string(head))
+		if branch, ok := strings.CutPrefix(ref, "ref: refs/heads/"); ok {
+			return branch
+		}
+		if len(ref) >= 12 {
+			return "detached at " + ref[:12]
+		}
+		return ""
+	}
+	return ""
+}
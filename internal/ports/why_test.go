@@ -0,0 +1,59 @@
+package ports
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestGitBranch(t *testing.T) {
+	repo := t.TempDir()
+	sub := filepath.Join(repo, "apps", "web")
+	if err := os.MkdirAll(filepath.Join(repo, ".git"), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(sub, 0o700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(repo, ".git", "HEAD"), []byte("ref: refs/heads/feature/cart\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if got := gitBranch(sub); got != "feature/cart" {
+		t.Errorf("gitBranch(subdir) = %q, want feature/cart", got)
+	}
+
+	// A worktree has a .git file pointing at its git dir.
+	wt, gitdir := t.TempDir(), t.TempDir()
+	if err := os.WriteFile(filepath.Join(wt, ".git"), []byte("gitdir: "+gitdir+"\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(gitdir, "HEAD"), []byte("0123456789abcdef0123456789abcdef01234567\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if got := gitBranch(wt); got != "detached at 0123456789ab" {
+		t.Errorf("gitBranch(worktree) = %q", got)
+	}
+}
+
+func TestStopAdvice(t *testing.T) {
+	tests := []struct {
+		name string
+		e    Explanation
+		p    Port
+		want string
+	}{
+		{"container", Explanation{}, Port{PID: 10, PortNum: 5432, DockerContainerName: "db"}, "docker stop db"},
+		{"system unit", Explanation{SystemdUnit: "nginx.service"}, Port{PID: 10, PortNum: 80}, "sudo systemctl stop nginx.service"},
+		{"user unit", Explanation{SystemdUnit: "api.service", UserUnit: true}, Port{PID: 10, PortNum: 8000}, "systemctl --user stop api.service"},
+		{"supervisor", Explanation{Supervisor: "PM2"}, Port{PID: 10, PortNum: 3000}, "pm2 stop"},
+		{"launcher group", Explanation{Launcher: &Ancestor{PID: 40, Name: "npm run dev"}, PGID: 40}, Port{PID: 42, PortNum: 3000}, "kill -TERM -- -40"},
+		{"launcher only", Explanation{Launcher: &Ancestor{PID: 40, Name: "turbo"}}, Port{PID: 42, PortNum: 3000}, "kill -TERM 40"},
+		{"plain", Explanation{}, Port{PID: 42, PortNum: 3000}, "tapas kill 3000"},
+	}
+	for _, tt := range tests {
+		if got := tt.e.stopAdvice(tt.p).Command; !strings.HasPrefix(got, tt.want) {
+			t.Errorf("%s: stop command = %q, want prefix %q", tt.name, got, tt.want)
+		}
+	}
+}
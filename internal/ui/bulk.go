@@ -0,0 +1,269 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/javiercepeda/tapas/internal/ports"
+	"github.com/muesli/termenv"
+)
+
+// bulkResult is the outcome of a bulk action for one row.
+type bulkResult struct {
+	target ports.Port
+	ok     bool
+	text   string
+}
+
+// bulkDoneMsg is sent when every target of a bulk kill/signal has finished.
+type bulkDoneMsg struct {
+	sig     syscall.Signal
+	results []bulkResult
+}
+
+// exportDoneMsg is sent after rows were exported to a JSON file.
+type exportDoneMsg struct {
+	path  string
+	count int
+	err   error
+}
+
+// rowKey identifies a row across refreshes (same PID on the same port).
+func rowKey(p ports.Port) string {
+	return fmt.Sprintf("%d/%d/%s", p.PID, p.PortNum, p.Protocol)
+}
+
+// markedPorts returns the marked rows in display order.
+func (m *Model) markedPorts() []ports.Port {
+	if len(m.marked) == 0 {
+		return nil
+	}
+	var out []ports.Port
+	seen := make(map[string]bool) // IPv4 and IPv6 sockets of one process share a key; act once
+	for _, p := range ports.FilterAndSort(m.ports, "", m.sortKey, false) {
+		if key := rowKey(p); m.marked[key] && !seen[key] {
+			seen[key] = true
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+// toggleMark marks or unmarks the selected row.
+func (m *Model) toggleMark() {
+	p := m.SelectedPort()
+	if p == nil {
+		return
+	}
+	if m.marked == nil {
+		m.marked = make(map[string]bool)
+	}
+	key := rowKey(*p)
+	if m.marked[key] {
+		delete(m.marked, key)
+	} else {
+		m.marked[key] = true
+	}
+}
+
+// markAllMatching marks every row matching the current filter, or unmarks them when all already are.
+func (m *Model) markAllMatching() {
+	disp := m.displayPorts()
+	if m.marked == nil {
+		m.marked = make(map[string]bool)
+	}
+	all := len(disp) > 0
+	for _, p := range disp {
+		if !m.marked[rowKey(p)] {
+			all = false
+			break
+		}
+	}
+	for _, p := range disp {
+		if all {
+			delete(m.marked, rowKey(p))
+		} else {
+			m.marked[rowKey(p)] = true
+		}
+	}
+}
+
+// pruneMarks drops marks for rows that disappeared after a refresh.
+func (m *Model) pruneMarks() {
+	if len(m.marked) == 0 {
+		return
+	}
+	present := make(map[string]bool, len(m.ports))
+	for _, p := range m.ports {
+		present[rowKey(p)] = true
+	}
+	for key := range m.marked {
+		if !present[key] {
+			delete(m.marked, key)
+		}
+	}
+}
+
+// actionTargets returns the marked rows, or the selected row when nothing is marked.
+func (m *Model) actionTargets() []ports.Port {
+	if marked := m.markedPorts(); len(marked) > 0 {
+		return marked
+	}
+	if p := m.SelectedPort(); p != nil {
+		return []ports.Port{*p}
+	}
+	return nil
+}
+
+// bulkCmd applies sig to every target concurrently (each kill may wait out its grace period).
+// Container rows are stopped with docker instead of signalling the host PID; non-terminating
+// signals skip them. Denied and protected rows are skipped: typed confirmation is per row.
+func (m Model) bulkCmd(targets []ports.Port, sig syscall.Signal) tea.Cmd {
+	opts := ports.TerminateOptions{Signal: sig, Grace: m.KillGrace, Escalate: m.AutoEscalate && sig != syscall.SIGKILL}
+	terminates := ports.LookupSignal(sig).Terminates
+	store := m.Relaunch
+	verdicts := make([]ports.Verdict, len(targets))
+	for i, p := range targets {
+		verdicts[i] = m.checkPolicy(p)
+	}
+	return func() tea.Msg {
+		results := make([]bulkResult, len(targets))
+		var wg sync.WaitGroup
+		for i, p := range targets {
+			wg.Add(1)
+			go func(i int, p ports.Port) {
+				defer wg.Done()
+				res := bulkResult{target: p}
+				verdict := verdicts[i]
+				switch {
+				case verdict.Decision == ports.Deny:
+					res.text = "denied by policy: " + verdict.Reason
+				case verdict.Decision == ports.Confirm:
+					res.text = "skipped: " + verdict.Reason + " (kill it on its own to confirm)"
+				case p.DockerContainerName != "" && terminates:
+					r := ports.RunContainerAction(p, ports.ContainerStop)
+					res.ok, res.text = r.OK, "container stopped"
+					if !r.OK {
+						res.text = r.Error
+					}
+				case p.DockerContainerName != "":
+					res.text = "skipped: container row (use container actions)"
+				case p.PID <= 0:
+					res.text = "skipped: PID unknown"
+				case terminates:
+					rec, canRelaunch := ports.NewRelaunchRecord(p, ports.RelaunchKilled)
+					r := ports.Terminate(p, opts)
+					recordKill(store, rec, canRelaunch && r.OK)
+					res.ok, res.text = r.OK && r.Exited, r.Summary()
+				default:
+					r := ports.SendSignal(p, sig)
+					res.ok, res.text = r.OK, "sent "+ports.LookupSignal(sig).Name
+					if !r.OK {
+						res.text = r.Error
+					}
+				}
+				results[i] = res
+			}(i, p)
+		}
+		wg.Wait()
+		return bulkDoneMsg{sig: sig, results: results}
+	}
+}
+
+// startBulk runs sig against the bulk targets with the progress spinner.
+func (m Model) startBulk(sig syscall.Signal) (Model, tea.Cmd) {
+	m.signalMenu = false
+	m.killing = true
+	m.killStarted = time.Now()
+	m.killResult = ""
+	return m, tea.Batch(m.bulkCmd(m.bulkTargets, sig), spinnerTick())
+}
+
+// copyRows copies one tab-separated line per row (port, pid, process, cwd, redacted command)
+// to the clipboard with OSC 52, which also works over SSH.
+func copyRows(rows []ports.Port) {
+	var lines []string
+	for _, p := range rows {
+		lines = append(lines, strings.Join([]string{
+			fmt.Sprint(p.PortNum), fmt.Sprint(p.PID), p.Process, p.WorkingDir, p.SafeCommand(),
+		}, "\t"))
+	}
+	termenv.Copy(strings.Join(lines, "\n"))
+}
+
+// exportCmd writes rows to a JSON file in the current directory.
+func exportCmd(rows []ports.Port) tea.Cmd {
+	return func() tea.Msg {
+		path, err := ports.ExportFile(".", rows)
+		return exportDoneMsg{path: path, count: len(rows), err: err}
+	}
+}
+
+// rowsLabel returns "1 row" or "N rows".
+func rowsLabel(n int) string {
+	if n == 1 {
+		return "1 row"
+	}
+	return fmt.Sprintf("%d rows", n)
+}
+
+// viewBulkConfirm lists every target of a bulk action before confirmation.
+func (m Model) viewBulkConfirm() string {
+	if m.killing {
+		body := fmt.Sprintf("Applying to %s...\n\n%s %s", rowsLabel(len(m.bulkTargets)), accentStyle.Render(m.spinner()),
+			dimStyle.Render(fmt.Sprintf("waiting for exit and port release (%.1fs)", time.Since(m.killStarted).Seconds())))
+		return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, modalStyle.Render(body))
+	}
+	if m.signalMenu {
+		return m.viewSignalMenu(nil)
+	}
+	lines := []string{fmt.Sprintf("Kill %s?", rowsLabel(len(m.bulkTargets))), ""}
+	for i, p := range m.bulkTargets {
+		if i == maxPreviewRows {
+			lines = append(lines, dimStyle.Render(fmt.Sprintf("  ... and %d more", len(m.bulkTargets)-maxPreviewRows)))
+			break
+		}
+		p := p
+		line := fmt.Sprintf("  %-6d %s", p.PortNum, truncate(ports.ProcessLabel(&p), 40))
+		switch v := m.checkPolicy(p); {
+		case v.Decision == ports.Deny:
+			line += errorStyle.Render("  (denied, skipped)")
+		case v.Decision == ports.Confirm:
+			line += errorStyle.Render("  (protected, skipped)")
+		case p.DockerContainerName != "":
+			line += dimStyle.Render("  (docker stop)")
+		case p.PID > 0:
+			line += dimStyle.Render(fmt.Sprintf("  PID %d", p.PID))
+		}
+		lines = append(lines, line)
+	}
+	lines = append(lines, "", "[y] Terminate all   [k] Force kill all   [s] Signal...   [n] Cancel")
+	if m.killResult != "" {
+		lines = append(lines, "", errorStyle.Render(m.killResult))
+	}
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, modalStyle.Render(strings.Join(lines, "\n")))
+}
+
+// viewBulkResults reports what happened to each row of a bulk action.
+func (m Model) viewBulkResults() string {
+	okSym, failSym := "✓", "✗"
+	if m.AsciiMode {
+		okSym, failSym = "+", "x"
+	}
+	lines := []string{"Results", ""}
+	for _, r := range m.bulkResults {
+		sym := successStyle.Render(okSym)
+		if !r.ok {
+			sym = errorStyle.Render(failSym)
+		}
+		p := r.target
+		lines = append(lines, fmt.Sprintf("%s %-6d %-20s %s", sym, p.PortNum, truncate(ports.ProcessLabel(&p), 20), truncate(r.text, 60)))
+	}
+	lines = append(lines, "", "[Enter] or [Esc] Close")
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, modalStyle.Render(strings.Join(lines, "\n")))
+}
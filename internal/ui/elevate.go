@@ -0,0 +1,63 @@
+package ui
+
+import (
+	"fmt"
+	"syscall"
+	"time"
+
+	"github.com/charmbracelet/bubbletea"
+	"github.com/javiercepeda/tapas/internal/ports"
+)
+
+// elevatedDoneMsg is sent when the privileged helper (sudo tapas signal ...) returns the terminal.
+type elevatedDoneMsg struct {
+	target ports.Port
+	sig    syscall.Signal
+	err    error
+}
+
+// startElevated retries the denied signal through sudo (or pkexec). The terminal is handed over
+// so the password prompt works; the TUI and its state resume afterwards.
+func (m Model) startElevated() (Model, tea.Cmd) {
+	if m.elevateSig == 0 || m.killTarget == nil {
+		return m, nil
+	}
+	p, sig := *m.killTarget, m.elevateSig
+	cmd, err := ports.ElevatedSignalCmd(p, sig)
+	if err != nil {
+		m.killResult = "Cannot retry as root: " + err.Error()
+		return m, nil
+	}
+	m.killResult = ""
+	m.elevateSig = 0
+	return m, tea.ExecProcess(cmd, func(err error) tea.Msg {
+		return elevatedDoneMsg{target: p, sig: sig, err: err}
+	})
+}
+
+// elevatedResultCmd records the helper's outcome in the user's audit log and, for terminating
+// signals, waits for exit and port release before reporting like a normal kill.
+func (m Model) elevatedResultCmd(msg elevatedDoneMsg) tea.Cmd {
+	grace := m.KillGrace
+	return func() tea.Msg {
+		ports.AuditElevated(msg.target, msg.sig, msg.err)
+		if msg.err != nil {
+			return signalDoneMsg{target: msg.target, sig: msg.sig, result: ports.KillResult{
+				OK: false, Error: fmt.Sprintf("Retry as root failed (%s)", msg.err)}}
+		}
+		if !ports.LookupSignal(msg.sig).Terminates {
+			return signalDoneMsg{target: msg.target, sig: msg.sig, result: ports.KillResult{OK: true}}
+		}
+		return killDoneMsg{target: msg.target, sig: msg.sig, result: ports.AwaitTermination(msg.target, grace)}
+	}
+}
+
+// handleElevatedDone shows the progress spinner while a root-signalled process is awaited.
+func (m Model) handleElevatedDone(msg elevatedDoneMsg) (Model, tea.Cmd) {
+	if msg.err == nil && ports.LookupSignal(msg.sig).Terminates {
+		m.killing = true
+		m.killStarted = time.Now()
+		return m, tea.Batch(m.elevatedResultCmd(msg), spinnerTick())
+	}
+	return m, m.elevatedResultCmd(msg)
+}
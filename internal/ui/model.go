@@ -2,8 +2,7 @@ package ui
 
 import (
 	"fmt"
-	"sort"
-	"strings"
+	"syscall"
 	"time"
 
 	"github.com/charmbracelet/bubbletea"
@@ -21,37 +20,57 @@ type refreshDoneMsg struct {
 	err   error
 }
 
-// killDoneMsg is sent after a kill attempt (from the same program, so no async needed; we can show result in Update).
+// killDoneMsg is sent when an asynchronous kill (signal, wait for exit and port release) finishes.
 type killDoneMsg struct {
-	ok    bool
-	error string
+	target ports.Port
+	sig    syscall.Signal
+	result ports.TerminateResult
 }
 
-// tickMsg is sent when watch-mode tick fires; triggers one refresh (efficient: one tick at a time).
-type tickMsg struct{}
+// signalDoneMsg is sent after a non-terminating signal (SIGHUP, SIGSTOP, ...) was delivered or failed.
+type signalDoneMsg struct {
+	target ports.Port
+	sig    syscall.Signal
+	result ports.KillResult
+}
 
-// SortKey is the current table sort key.
-type SortKey int
+// planDoneMsg is sent when the process group / tree for a scoped kill has been resolved.
+type planDoneMsg struct {
+	scope ports.KillScope
+	plan  ports.KillPlan
+	err   error
+}
 
-const (
-	SortByPort   SortKey = iota
-	SortByUptime
-	SortByProcess
-)
+// containerDoneMsg is sent when docker stop/restart/pause/unpause finishes.
+type containerDoneMsg struct {
+	target ports.Port
+	action ports.ContainerAction
+	result ports.KillResult
+}
 
-func (k SortKey) String() string {
-	switch k {
-	case SortByPort:
-		return "Port"
-	case SortByUptime:
-		return "Uptime"
-	case SortByProcess:
-		return "Process"
-	default:
-		return "Port"
-	}
+// execDoneMsg is sent when a program that was handed the terminal (docker logs / exec) exits.
+type execDoneMsg struct {
+	err error
 }
 
+// spinnerTickMsg advances the progress spinner while a kill is in flight.
+type spinnerTickMsg struct{}
+
+// spinnerInterval is the frame time of the kill progress spinner.
+const spinnerInterval = 100 * time.Millisecond
+
+// historyMsg carries the recent history of one port for the details modal.
+type historyMsg struct {
+	port   uint16
+	events []ports.HistoryEvent
+}
+
+// maxHistoryShown is how many history events the details modal lists.
+const maxHistoryShown = 6
+
+// tickMsg is sent when watch-mode tick fires; triggers one refresh (efficient: one tick at a time).
+type tickMsg struct{}
+
 // Model is the root Bubble Tea model.
 type Model struct {
 	ports     []ports.Port
@@ -62,23 +81,84 @@ type Model struct {
 	height    int
 
 	// v0.2: sort and filter
-	sortKey     SortKey
+	sortKey     ports.SortKey
 	searchMode  bool
 	searchQuery string
+	staleOnly   bool // show only stale listeners (orphaned, cwd deleted, exe replaced)
 
 	// Modals (MVP: details and kill confirm)
 	showDetails     bool
+	revealCommand   bool                 // details modal shows the raw, unredacted command (local user only; reset on close)
+	history         []ports.HistoryEvent // recent history of historyPort, loaded when the details modal opens
+	historyPort     uint16
 	showKillConfirm bool
 	killTarget      *ports.Port
 	killResult      string // error message after failed kill
 	successMsg      string // e.g. "Port 3000 terminated."
 
+	// Async kill progress: spinner while waiting for exit and port release.
+	killing      bool
+	killStarted  time.Time
+	spinnerFrame int
+	killOutlived bool           // process outlived the grace period; modal offers force kill
+	outlivedSig  syscall.Signal // the signal it outlived; [y] sends it again
+
+	// Signal menu inside the kill modal ([s]): pick any of ports.Signals.
+	signalMenu  bool
+	signalIndex int
+
+	// Kill scope ([m] in the kill modal): process only, process group, or entire tree.
+	// killPlan is the resolved preview for group/tree; nil while resolving or for process scope.
+	killScope   ports.KillScope
+	killPlan    *ports.KillPlan
+	killPlanErr string
+
+	// Safety policy for the kill target over all its ports (ports.CheckPolicyPorts, tightened by the
+	// scope preview): Deny blocks every action; Confirm requires typing verdict.Port (confirmInput)
+	// before the usual keys work.
+	verdict      ports.Verdict
+	confirmInput string
+	confirmed    bool
+
+	// elevateSig is the signal that just failed with EPERM; [p] retries it as root through the helper.
+	elevateSig syscall.Signal
+
+	// Container rows get container actions in the kill modal; hostKill switches to signalling the host PID.
+	hostKill bool
+
+	// Multi-select: [Space] marks rows, [a] marks all matching the filter. Keys are rowKey(p).
+	// bulkTargets is set while the bulk kill modal is open; bulkResults while per-row results are shown.
+	marked      map[string]bool
+	bulkTargets []ports.Port
+	bulkResults []bulkResult
+
+	// Recently killed list ([u]): relaunch records loaded from Relaunch.
+	showRelaunch    bool
+	relaunchRecords []ports.RelaunchRecord
+	relaunchIndex   int
+
 	// v1.0 Watch mode: auto-refresh every WatchInterval (no heavy polling; one tick in flight).
 	WatchEnabled  bool
 	WatchInterval time.Duration
 
 	// AsciiMode: use ASCII indicators only (! public, - Docker); no Unicode ●○.
 	AsciiMode bool
+
+	// KillGrace is how long a kill waits for exit and port release (0 = ports.DefaultGracePeriod).
+	// AutoEscalate sends SIGKILL without asking when the process outlives it.
+	KillGrace    time.Duration
+	AutoEscalate bool
+
+	// Relaunch keeps a relaunch record for every kill and powers restart and the recently killed list.
+	// Nil disables both.
+	Relaunch *ports.RelaunchStore
+
+	// Activity records connection activity on every refresh, for idle detection (tapas prune --idle).
+	Activity *ports.ActivityStore
+
+	// PickMode turns the table into a picker (tapas pick): Enter quits with the chosen rows in picked.
+	PickMode bool
+	picked   []ports.Port
 }
 
 // NewModel returns an initial model. Caller must provide a Lister (e.g. ports.DefaultLister()).
@@ -106,6 +186,39 @@ func (m Model) refreshCmd() tea.Cmd {
 	}
 }
 
+// observeCmd records connection activity for the listing off the UI goroutine. Best effort.
+func (m Model) observeCmd(list []ports.Port) tea.Cmd {
+	store := m.Activity
+	if store == nil {
+		return nil
+	}
+	return func() tea.Msg {
+		_, _ = store.Observe(list)
+		return nil
+	}
+}
+
+// historyCmd loads the recorded history of port for the details modal. No-op when history is off.
+func (m Model) historyCmd(port uint16) tea.Cmd {
+	if ports.HistoryLogPath() == "" {
+		return nil
+	}
+	return func() tea.Msg {
+		events, _ := ports.ReadHistory()
+		var matched []ports.HistoryEvent
+		q := ports.HistoryQuery{Port: port}
+		for _, e := range events {
+			if q.Match(e) {
+				matched = append(matched, e)
+			}
+		}
+		if len(matched) > maxHistoryShown {
+			matched = matched[len(matched)-maxHistoryShown:]
+		}
+		return historyMsg{port: port, events: matched}
+	}
+}
+
 // scheduleTick returns a Cmd that sends tickMsg after WatchInterval (for watch mode).
 func (m Model) scheduleTick() tea.Cmd {
 	return tea.Tick(m.WatchInterval, func(t time.Time) tea.Msg {
@@ -113,75 +226,224 @@ func (m Model) scheduleTick() tea.Cmd {
 	})
 }
 
-// displayPorts returns filtered and sorted ports for display. Selection index applies to this slice.
-func (m *Model) displayPorts() []ports.Port {
-	return filterAndSort(m.ports, m.searchQuery, m.sortKey)
+// killCmd runs ports.Terminate with sig off the UI goroutine and reports back with killDoneMsg.
+// With a plan (group or tree scope) every planned process is signalled, children first.
+func (m Model) killCmd(p ports.Port, plan *ports.KillPlan, sig syscall.Signal) tea.Cmd {
+	opts := ports.TerminateOptions{Signal: sig, Grace: m.KillGrace, Escalate: m.AutoEscalate && sig != syscall.SIGKILL}
+	store := m.Relaunch
+	return func() tea.Msg {
+		rec, canRelaunch := ports.NewRelaunchRecord(p, ports.RelaunchKilled) // before the environment is gone
+		var result ports.TerminateResult
+		if plan != nil {
+			result = plan.Terminate(opts)
+		} else {
+			result = ports.Terminate(p, opts)
+		}
+		recordKill(store, rec, canRelaunch && result.OK)
+		return killDoneMsg{target: p, sig: sig, result: result}
+	}
 }
 
-func filterAndSort(list []ports.Port, query string, sortKey SortKey) []ports.Port {
-	var out []ports.Port
-	q := strings.TrimSpace(strings.ToLower(query))
-	for _, p := range list {
-		if q == "" || portMatches(p, q) {
-			out = append(out, p)
+// signalCmd sends a non-terminating signal off the UI goroutine and reports back with signalDoneMsg.
+func signalCmd(p ports.Port, plan *ports.KillPlan, sig syscall.Signal) tea.Cmd {
+	return func() tea.Msg {
+		if plan != nil {
+			return signalDoneMsg{target: p, sig: sig, result: plan.Signal(sig)}
 		}
+		return signalDoneMsg{target: p, sig: sig, result: ports.SendSignal(p, sig)}
+	}
+}
+
+// planCmd resolves the processes a group/tree kill would affect, for the modal preview.
+func (m Model) planCmd(p ports.Port, scope ports.KillScope) tea.Cmd {
+	listing := m.ports
+	return func() tea.Msg {
+		plan, err := ports.PlanKill(p, scope, listing)
+		return planDoneMsg{scope: scope, plan: plan, err: err}
 	}
-	sort.SliceStable(out, func(i, j int) bool {
-		return lessPort(out[i], out[j], sortKey)
+}
+
+// containerCmd runs a docker action off the UI goroutine and reports back with containerDoneMsg.
+func containerCmd(p ports.Port, action ports.ContainerAction) tea.Cmd {
+	return func() tea.Msg {
+		return containerDoneMsg{target: p, action: action, result: ports.RunContainerAction(p, action)}
+	}
+}
+
+// containerModal reports whether the kill modal shows container actions instead of signals.
+func (m Model) containerModal() bool {
+	return m.killTarget != nil && m.killTarget.DockerContainerName != "" && !m.hostKill
+}
+
+// updateContainerModal handles keys for container rows: stop, restart, pause, logs, shell.
+func (m Model) updateContainerModal(msg tea.KeyMsg) (Model, tea.Cmd) {
+	p := *m.killTarget
+	startAction := func(action ports.ContainerAction) (Model, tea.Cmd) {
+		m.killing = true
+		m.killStarted = time.Now()
+		m.killResult = ""
+		return m, tea.Batch(containerCmd(p, action), spinnerTick())
+	}
+	switch msg.String() {
+	case "s", "S", "y", "Y":
+		return startAction(ports.ContainerStop)
+	case "r", "R":
+		return startAction(ports.ContainerRestart)
+	case "p", "P":
+		return startAction(ports.ContainerPause)
+	case "u", "U":
+		return startAction(ports.ContainerUnpause)
+	case "l", "L":
+		return m, tea.ExecProcess(ports.ContainerLogsCmd(p.DockerContainerName), func(err error) tea.Msg {
+			return execDoneMsg{err: err}
+		})
+	case "e", "E":
+		return m, tea.ExecProcess(ports.ContainerShellCmd(p.DockerContainerName), func(err error) tea.Msg {
+			return execDoneMsg{err: err}
+		})
+	case "h", "H":
+		// Explicit opt-in only: the host PID is usually docker-proxy, not the container.
+		m.hostKill = true
+		return m, nil
+	case "n", "N", "q", "esc":
+		m.showKillConfirm = false
+		m.killTarget = nil
+		return m, nil
+	}
+	return m, nil
+}
+
+// cycleKillScope moves to the next kill scope and resolves its preview.
+func (m Model) cycleKillScope() (Model, tea.Cmd) {
+	m.killScope = (m.killScope + 1) % 3
+	m.killPlan = nil
+	m.killPlanErr = ""
+	if m.killTarget == nil {
+		return m, nil
+	}
+	m.verdict = m.checkPolicy(*m.killTarget)
+	if m.killScope == ports.ScopeProcess {
+		return m, nil
+	}
+	return m, m.planCmd(*m.killTarget, m.killScope)
+}
+
+func spinnerTick() tea.Cmd {
+	return tea.Tick(spinnerInterval, func(time.Time) tea.Msg {
+		return spinnerTickMsg{}
 	})
-	return out
 }
 
-func portMatches(p ports.Port, q string) bool {
-	if strings.Contains(strings.ToLower(fmt.Sprint(p.PortNum)), q) {
-		return true
+// startKill sends sig to the kill target. Terminating signals switch the modal into progress
+// mode (wait for exit and port release); others are sent and reported immediately.
+func (m Model) startKill(sig syscall.Signal) (Model, tea.Cmd) {
+	if len(m.bulkTargets) > 0 {
+		return m.startBulk(sig)
 	}
-	if strings.Contains(strings.ToLower(p.Process), q) {
-		return true
+	if m.killTarget == nil || m.killTarget.PID <= 0 {
+		return m, nil
 	}
-	if strings.Contains(strings.ToLower(p.Project()), q) {
-		return true
+	if m.killScope != ports.ScopeProcess && m.killPlan == nil {
+		return m, nil // preview still resolving (or failed): never signal an unconfirmed set
 	}
-	if p.ProjectDisplayName != "" && strings.Contains(strings.ToLower(p.ProjectDisplayName), q) {
-		return true
+	m.signalMenu = false
+	m.killResult = ""
+	if !ports.LookupSignal(sig).Terminates {
+		return m, signalCmd(*m.killTarget, m.killPlan, sig)
 	}
-	if strings.Contains(strings.ToLower(p.WorkingDir), q) {
-		return true
+	m.killing = true
+	m.killStarted = time.Now()
+	return m, tea.Batch(m.killCmd(*m.killTarget, m.killPlan, sig), spinnerTick())
+}
+
+// checkPolicy returns the verdict for signalling p's process, over every port it holds.
+func (m Model) checkPolicy(p ports.Port) ports.Verdict {
+	return ports.CheckPolicyPorts(p, m.heldPorts(p.PID))
+}
+
+// heldPorts returns the ports pid listens on in the current listing.
+func (m Model) heldPorts(pid int) []uint16 {
+	if pid <= 0 {
+		return nil
 	}
-	if p.Framework != "" && strings.Contains(strings.ToLower(p.Framework), q) {
-		return true
+	var out []uint16
+	for _, p := range m.ports {
+		if p.PID == pid {
+			out = append(out, p.PortNum)
+		}
 	}
-	if p.DockerContainerName != "" && strings.Contains(strings.ToLower(p.DockerContainerName), q) {
-		return true
+	return out
+}
+
+// updatePolicyGate handles keys while the policy blocks the kill modal. Denied targets can only be
+// dismissed; protected ones accept the verdict's port number followed by Enter. Reports false once unlocked.
+// Bulk kills are not gated here: bulkCmd checks the policy per row.
+func (m Model) updatePolicyGate(msg tea.KeyMsg) (Model, tea.Cmd, bool) {
+	if len(m.bulkTargets) > 0 || m.verdict.Decision == ports.Allow || (m.verdict.Decision == ports.Confirm && m.confirmed) {
+		return m, nil, false
 	}
-	if p.DockerImage != "" && strings.Contains(strings.ToLower(p.DockerImage), q) {
-		return true
+	switch key := msg.String(); key {
+	case "esc", "q", "n", "N":
+		m.showKillConfirm = false
+		m.killTarget = nil
+		m.bulkTargets = nil
+		m.confirmInput = ""
+	case "backspace":
+		if len(m.confirmInput) > 0 {
+			m.confirmInput = m.confirmInput[:len(m.confirmInput)-1]
+		}
+	case "enter":
+		if m.verdict.Decision == ports.Confirm {
+			if m.confirmInput == fmt.Sprint(m.verdict.Port) {
+				m.confirmed = true
+				m.killResult = ""
+			} else {
+				m.killResult = "Port number does not match."
+			}
+			m.confirmInput = ""
+		}
+	default:
+		if m.verdict.Decision == ports.Confirm && len(key) == 1 && key[0] >= '0' && key[0] <= '9' && len(m.confirmInput) < 5 {
+			m.confirmInput += key
+		}
 	}
-	if p.Environment != "" && strings.Contains(strings.ToLower(p.Environment), q) {
-		return true
+	return m, nil, true
+}
+
+// offerElevation enables [p] Retry with sudo when sig was refused with EPERM for a single process
+// (scoped kills signal many processes; retrying them one by one as root is not offered).
+func (m *Model) offerElevation(r ports.KillResult, sig syscall.Signal) {
+	if r.NeedsPrivilege && m.killScope == ports.ScopeProcess && len(m.bulkTargets) == 0 {
+		m.elevateSig = sig
 	}
-	return false
 }
 
-func lessPort(a, b ports.Port, sortKey SortKey) bool {
-	switch sortKey {
-	case SortByPort:
-		return a.PortNum < b.PortNum
-	case SortByUptime:
-		ua, ub := a.Uptime(), b.Uptime()
-		if ua != ub {
-			return ua > ub // descending: longest uptime first
+// updateSignalMenu handles keys while the signal menu is open: arrows/enter or a digit to pick.
+func (m Model) updateSignalMenu(msg tea.KeyMsg) (Model, tea.Cmd) {
+	switch key := msg.String(); key {
+	case "up":
+		if m.signalIndex > 0 {
+			m.signalIndex--
 		}
-		return a.PortNum < b.PortNum
-	case SortByProcess:
-		pa, pb := strings.ToLower(a.Process), strings.ToLower(b.Process)
-		if pa != pb {
-			return pa < pb
+	case "down", "j":
+		if m.signalIndex < len(ports.Signals)-1 {
+			m.signalIndex++
 		}
-		return a.PortNum < b.PortNum
+	case "enter":
+		return m.startKill(ports.Signals[m.signalIndex].Signal)
+	case "esc", "q", "s":
+		m.signalMenu = false
 	default:
-		return a.PortNum < b.PortNum
+		if len(key) == 1 && key[0] >= '1' && int(key[0]-'1') < len(ports.Signals) {
+			return m.startKill(ports.Signals[key[0]-'1'].Signal)
+		}
 	}
+	return m, nil
+}
+
+// displayPorts returns filtered and sorted ports for display. Selection index applies to this slice.
+func (m *Model) displayPorts() []ports.Port {
+	return ports.FilterAndSort(m.ports, m.searchQuery, m.sortKey, m.staleOnly)
 }
 
 // SelectedPort returns the currently selected port, or nil if none (from display list).
@@ -211,57 +473,98 @@ func (m *Model) clampSelected() {
 	}
 }
 
-// Update handles messages. UI does not execute OS commands; kill is done via ports.Kill in response to confirm.
+// Update handles messages. UI does not execute OS commands; kill is done via ports.KillPort in response to confirm.
+// The kill target is the Port snapshot taken when the modal opened; ports verifies the PID was not reused.
 func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
 		if m.showKillConfirm {
+			if m.killing {
+				// Kill in flight: only allow quitting; the result arrives as killDoneMsg.
+				if msg.String() == "ctrl+c" {
+					return m, tea.Quit
+				}
+				return m, nil
+			}
+			if len(m.bulkTargets) > 0 && !m.signalMenu {
+				switch msg.String() {
+				case "y", "Y":
+					return m.startBulk(syscall.SIGTERM)
+				case "k", "K":
+					return m.startBulk(syscall.SIGKILL)
+				case "s", "S":
+					m.signalMenu = true
+					m.signalIndex = 0
+				case "n", "N", "q", "esc":
+					m.showKillConfirm = false
+					m.bulkTargets = nil
+				}
+				return m, nil
+			}
+			if m, cmd, gated := m.updatePolicyGate(msg); gated {
+				return m, cmd
+			}
+			if m.containerModal() {
+				return m.updateContainerModal(msg)
+			}
+			if m.signalMenu {
+				return m.updateSignalMenu(msg)
+			}
 			switch msg.String() {
 			case "y", "Y":
-				if m.killTarget != nil && m.killTarget.PID > 0 {
-					p := m.killTarget
-					r := ports.KillPort(p.PortNum, p.PID)
-					if r.OK {
-						m.showKillConfirm = false
-						m.killTarget = nil
-						m.killResult = ""
-						m.successMsg = fmt.Sprintf("Port %d terminated.", p.PortNum)
-						return m, m.refreshCmd()
-					}
-					m.killResult = r.Error
-					return m, nil
+				if m.killOutlived {
+					return m.startKill(m.outlivedSig)
 				}
+				return m.startKill(syscall.SIGTERM)
 			case "K", "k":
 				// k in dialog = force kill (so shift+K and k both work)
-				if m.killTarget != nil && m.killTarget.PID > 0 {
-					p := m.killTarget
-					r := ports.KillPortForce(p.PortNum, p.PID)
-					if r.OK {
-						m.showKillConfirm = false
-						m.killTarget = nil
-						m.killResult = ""
-						m.successMsg = fmt.Sprintf("Port %d force-killed.", p.PortNum)
-						return m, m.refreshCmd()
-					}
-					m.killResult = r.Error
-					return m, nil
-				}
+				return m.startKill(syscall.SIGKILL)
+			case "s", "S":
+				m.signalMenu = true
+				m.signalIndex = 0
+				return m, nil
+			case "m", "M":
+				return m.cycleKillScope()
+			case "r", "R":
+				return m.startRestart()
+			case "p", "P":
+				return m.startElevated()
 			case "n", "N", "q", "esc":
 				m.showKillConfirm = false
 				m.killTarget = nil
+				m.killOutlived = false
 				// Keep killResult so main view can show it until user presses another key
 				return m, nil
 			}
 			return m, nil
 		}
+		if m.bulkResults != nil {
+			switch msg.String() {
+			case "enter", "esc", "q":
+				m.bulkResults = nil
+			}
+			return m, nil
+		}
+		if m.showRelaunch {
+			return m.updateRelaunchList(msg)
+		}
 		if m.showDetails {
 			switch msg.String() {
 			case "q", "esc":
 				m.showDetails = false
+				m.revealCommand = false
+				return m, nil
+			case "v", "V":
+				m.revealCommand = !m.revealCommand
 				return m, nil
 			}
 			return m, nil
 		}
+		if m.PickMode {
+			if m, cmd, handled := m.updatePick(msg); handled {
+				return m, cmd
+			}
+		}
 		// Search mode: only Esc and backspace and runes
 		if m.searchMode {
 			switch msg.String() {
@@ -309,20 +612,85 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 			return m, nil
 		case "enter":
-			if m.SelectedPort() != nil {
+			if p := m.SelectedPort(); p != nil {
 				m.showDetails = true
+				return m, m.historyCmd(p.PortNum)
 			}
 			return m, nil
+		case " ":
+			m.toggleMark()
+			if disp := m.displayPorts(); m.selected < len(disp)-1 {
+				m.selected++ // advance so Space can mark a run of rows
+			}
+			return m, nil
+		case "a", "A":
+			m.markAllMatching()
+			return m, nil
+		case "esc":
+			m.marked = nil
+			return m, nil
+		case "c":
+			if rows := m.actionTargets(); len(rows) > 0 {
+				copyRows(rows)
+				m.successMsg = fmt.Sprintf("Copied %s to clipboard.", rowsLabel(len(rows)))
+			}
+			return m, nil
+		case "x", "X":
+			rows := m.markedPorts()
+			if len(rows) == 0 {
+				rows = m.displayPorts()
+			}
+			if len(rows) == 0 {
+				return m, nil
+			}
+			return m, exportCmd(rows)
 		case "k":
+			if ports.ReadOnly() {
+				m.killResult = "Read-only mode: kill actions are disabled."
+				return m, nil
+			}
+			if marked := m.markedPorts(); len(marked) > 0 {
+				m.showKillConfirm = true
+				m.killResult = ""
+				m.signalMenu = false
+				m.bulkTargets = marked
+				m.verdict = ports.Verdict{Decision: ports.Allow}
+				m.confirmed = false
+				m.confirmInput = ""
+				return m, nil
+			}
 			if p := m.SelectedPort(); p != nil {
 				m.showKillConfirm = true
 				m.killResult = ""
+				m.killOutlived = false
+				m.signalMenu = false
+				m.killScope = ports.ScopeProcess
+				m.killPlan = nil
+				m.killPlanErr = ""
+				m.hostKill = false
+				m.elevateSig = 0
+				m.bulkTargets = nil
+				m.bulkResults = nil
+				m.verdict = m.checkPolicy(*p)
+				m.confirmInput = ""
+				m.confirmed = false
 				dup := *p
 				m.killTarget = &dup
 			}
 			return m, nil
+		case "u", "U":
+			if ports.ReadOnly() {
+				m.killResult = "Read-only mode: relaunch is disabled."
+				return m, nil
+			}
+			if m.Relaunch == nil {
+				return m, nil
+			}
+			m.showRelaunch = true
+			m.relaunchIndex = 0
+			return m, m.loadRelaunchCmd()
 		case "s", "S":
-			m.sortKey = SortKey((int(m.sortKey) + 1) % 3)
+			m.sortKey = ports.SortKey((int(m.sortKey) + 1) % 3)
 			m.clampSelected()
 			return m, nil
 		case "w", "W":
@@ -334,6 +702,10 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case "/":
 			m.searchMode = true
 			return m, nil
+		case "o", "O":
+			m.staleOnly = !m.staleOnly
+			m.clampSelected()
+			return m, nil
 		}
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
@@ -344,6 +716,146 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, tea.Batch(m.refreshCmd(), m.scheduleTick())
 		}
 		return m, nil
+	case spinnerTickMsg:
+		if m.killing {
+			m.spinnerFrame++
+			return m, spinnerTick()
+		}
+		return m, nil
+	case killDoneMsg:
+		m.killing = false
+		r := msg.result
+		if !r.OK {
+			m.killResult = fmt.Sprintf("Failed to kill port %d (%s)", msg.target.PortNum, r.Error)
+			m.offerElevation(r.KillResult, msg.sig)
+			return m, nil
+		}
+		if !r.Exited {
+			// Still alive after the grace period: keep the modal open and offer SIGKILL.
+			m.killOutlived, m.outlivedSig = true, msg.sig
+			m.killResult = fmt.Sprintf("Port %d: %s.", msg.target.PortNum, r.Summary())
+			return m, nil
+		}
+		m.showKillConfirm = false
+		m.killTarget = nil
+		m.killOutlived = false
+		m.killResult = ""
+		verb := "terminated"
+		switch msg.sig {
+		case syscall.SIGKILL:
+			verb = "force-killed"
+		case syscall.SIGINT:
+			verb = "interrupted"
+		}
+		m.successMsg = fmt.Sprintf("Port %d %s: %s.", msg.target.PortNum, verb, r.Summary())
+		return m, m.refreshCmd()
+	case bulkDoneMsg:
+		m.killing = false
+		m.showKillConfirm = false
+		m.bulkTargets = nil
+		m.bulkResults = msg.results
+		m.marked = nil
+		failed := 0
+		for _, r := range msg.results {
+			if !r.ok {
+				failed++
+			}
+		}
+		if failed > 0 {
+			m.killResult = fmt.Sprintf("%d of %s failed.", failed, rowsLabel(len(msg.results)))
+		} else {
+			m.successMsg = fmt.Sprintf("%s: %s applied.", rowsLabel(len(msg.results)), ports.LookupSignal(msg.sig).Name)
+		}
+		return m, m.refreshCmd()
+	case exportDoneMsg:
+		if msg.err != nil {
+			m.killResult = "Export failed: " + msg.err.Error()
+			return m, nil
+		}
+		m.successMsg = fmt.Sprintf("Exported %s to %s.", rowsLabel(msg.count), msg.path)
+		return m, nil
+	case containerDoneMsg:
+		m.killing = false
+		if !msg.result.OK {
+			m.killResult = msg.result.Error
+			return m, nil
+		}
+		m.showKillConfirm = false
+		m.killTarget = nil
+		m.killResult = ""
+		m.successMsg = fmt.Sprintf("Container %s %s (port %d).", msg.target.DockerContainerName, msg.action.PastTense(), msg.target.PortNum)
+		return m, m.refreshCmd()
+	case execDoneMsg:
+		if msg.err != nil {
+			m.killResult = msg.err.Error()
+		}
+		return m, m.refreshCmd()
+	case planDoneMsg:
+		if !m.showKillConfirm || msg.scope != m.killScope {
+			return m, nil // modal closed or scope changed again meanwhile
+		}
+		if msg.err != nil {
+			m.killPlanErr = msg.err.Error()
+			return m, nil
+		}
+		plan := msg.plan
+		m.killPlan = &plan
+		// Other processes in the group/tree may hold protected or denied ports: ask again.
+		if v := plan.PolicyVerdict(); v.Decision >= m.verdict.Decision && v != m.verdict {
+			m.verdict = v
+			m.confirmed = false
+		}
+		return m, nil
+	case restartDoneMsg:
+		m.killing = false
+		r := msg.result
+		switch {
+		case msg.err != nil:
+			m.killResult = fmt.Sprintf("Restart port %d: %s", msg.target.PortNum, msg.err)
+		case !r.OK:
+			m.killResult = fmt.Sprintf("Failed to kill port %d (%s)", msg.target.PortNum, r.Error)
+		case !r.Exited || !r.PortFree:
+			m.killOutlived, m.outlivedSig = !r.Exited, r.Signal
+			m.killResult = fmt.Sprintf("Port %d: %s; not relaunched.", msg.target.PortNum, r.Summary())
+		default:
+			m.showKillConfirm = false
+			m.killTarget = nil
+			m.killResult = ""
+			m.successMsg = fmt.Sprintf("Port %d restarted (PID %d, output in %s).", msg.target.PortNum, msg.pid, msg.log)
+			return m, m.refreshCmd()
+		}
+		return m, nil
+	case relaunchListMsg:
+		if msg.err != nil {
+			m.killResult = msg.err.Error()
+		}
+		m.relaunchRecords = msg.records
+		return m, nil
+	case relaunchDoneMsg:
+		if msg.err != nil {
+			m.killResult = fmt.Sprintf("Relaunch port %d: %s", msg.record.Port, msg.err)
+			return m, nil
+		}
+		m.showRelaunch = false
+		m.killResult = ""
+		m.successMsg = fmt.Sprintf("Relaunched port %d (PID %d, output in %s).", msg.record.Port, msg.pid, msg.log)
+		return m, m.refreshCmd()
+	case elevatedDoneMsg:
+		return m.handleElevatedDone(msg)
+	case signalDoneMsg:
+		if !msg.result.OK {
+			m.killResult = msg.result.Error
+			m.offerElevation(msg.result, msg.sig)
+			return m, nil
+		}
+		m.showKillConfirm = false
+		m.killTarget = nil
+		m.killResult = ""
+		m.successMsg = fmt.Sprintf("Sent %s to port %d (%s).", ports.LookupSignal(msg.sig).Name, msg.target.PortNum, msg.target.Process)
+		return m, m.refreshCmd()
+	case historyMsg:
+		m.history, m.historyPort = msg.events, msg.port
+		return m, nil
 	case refreshDoneMsg:
 		m.err = ""
 		if msg.err != nil {
@@ -351,9 +863,11 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.successMsg = ""
 			return m, nil
 		}
+		exited := m.recordExitedCmd(m.ports, msg.ports)
 		m.ports = msg.ports
+		m.pruneMarks()
 		m.clampSelected()
-		return m, nil
+		return m, tea.Batch(exited, m.observeCmd(msg.ports))
 	}
 	return m, nil
 }
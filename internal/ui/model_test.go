@@ -0,0 +1,64 @@
+package ui
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/javiercepeda/tapas/internal/ports"
+)
+
+// testModel returns a model listing sshd on 22 (denied by the built-in policy) and node on 3000.
+func testModel() Model {
+	m := NewModel(nil, true)
+	m.ports = []ports.Port{
+		{PID: 1 << 30, PortNum: 22, Process: "sshd"},
+		{PID: 1<<30 + 1, PortNum: 3000, Process: "node"},
+	}
+	return m
+}
+
+// press sends keys to m in order; commands are returned but never run, so nothing is signalled.
+func press(m Model, keys ...string) (Model, tea.Cmd) {
+	var cmd tea.Cmd
+	for _, k := range keys {
+		msg := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(k)}
+		switch k {
+		case "esc":
+			msg = tea.KeyMsg{Type: tea.KeyEsc}
+		case "enter":
+			msg = tea.KeyMsg{Type: tea.KeyEnter}
+		case "down":
+			msg = tea.KeyMsg{Type: tea.KeyDown}
+		case " ":
+			msg = tea.KeyMsg{Type: tea.KeySpace}
+		}
+		var next tea.Model
+		next, cmd = m.Update(msg)
+		m = next.(Model)
+	}
+	return m, cmd
+}
+
+func TestBulkSignalMenuAfterDeniedRow(t *testing.T) {
+	// Cancel a kill of the denied sshd row, then mark node and pick a signal for the bulk kill.
+	m, _ := press(testModel(), "k", "esc", "down", " ", "k", "s", "1")
+	if !m.killing {
+		t.Fatalf("bulk signal menu did not start the kill (verdict %+v, menu %v)", m.verdict, m.signalMenu)
+	}
+	if len(m.bulkTargets) != 1 || m.bulkTargets[0].PortNum != 3000 {
+		t.Errorf("bulk targets = %v, want node on 3000", m.bulkTargets)
+	}
+}
+
+func TestSingleKillClearsStaleBulkTargets(t *testing.T) {
+	// A bulk modal left behind (e.g. by an older cancel path) must not capture the next single-row kill.
+	m := testModel()
+	m.bulkTargets = []ports.Port{m.ports[0]}
+	m, _ = press(m, "esc", "down", "k")
+	if len(m.bulkTargets) != 0 {
+		t.Fatalf("single-row kill kept stale bulk targets %v", m.bulkTargets)
+	}
+	if m.killTarget == nil || m.killTarget.PortNum != 3000 {
+		t.Errorf("kill target = %v, want node on 3000", m.killTarget)
+	}
+}
@@ -0,0 +1,51 @@
+package ui
+
+import (
+	"github.com/charmbracelet/bubbletea"
+	"github.com/javiercepeda/tapas/internal/ports"
+)
+
+// pickStatusBar replaces the action keys in picker mode: nothing destructive, Enter picks.
+const pickStatusBar = "[Enter] Pick   [Space] Mark   [a] All   [/] Search   [s] Sort   [o] Stale   [r] Refresh   [w] Watch   [q] Cancel"
+
+// SetFilter presets the search query, sort order and stale-only toggle (tapas pick --filter, --sort).
+func (m *Model) SetFilter(query string, key ports.SortKey, staleOnly bool) {
+	m.searchQuery = query
+	m.sortKey = key
+	m.staleOnly = staleOnly
+}
+
+// Picked returns the rows chosen with Enter in picker mode, or nil when the user cancelled.
+func (m Model) Picked() []ports.Port {
+	return m.picked
+}
+
+// updatePick handles picker keys. Enter picks the marked rows (or the selected row), also while
+// typing a search, like fzf. Kill, relaunch, copy and export are off; handled is false for keys
+// the table handles as usual.
+func (m Model) updatePick(msg tea.KeyMsg) (Model, tea.Cmd, bool) {
+	switch msg.String() {
+	case "enter":
+		if targets := m.actionTargets(); len(targets) > 0 {
+			m.picked = targets
+			return m, tea.Quit, true
+		}
+		return m, nil, true
+	case "ctrl+c":
+		return m, tea.Quit, true
+	}
+	if m.searchMode {
+		return m, nil, false
+	}
+	switch msg.String() {
+	case "q":
+		return m, tea.Quit, true
+	case "esc":
+		if len(m.marked) == 0 {
+			return m, tea.Quit, true
+		}
+	case "k", "K", "u", "U", "x", "X", "c":
+		return m, nil, true
+	}
+	return m, nil, false
+}
@@ -0,0 +1,188 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/javiercepeda/tapas/internal/ports"
+)
+
+// restartDoneMsg is sent when a restart (kill, wait for port release, relaunch) finishes.
+type restartDoneMsg struct {
+	target ports.Port
+	result ports.TerminateResult
+	pid    int
+	log    string
+	err    error
+}
+
+// relaunchListMsg carries the recently killed list loaded from the state file.
+type relaunchListMsg struct {
+	records []ports.RelaunchRecord
+	err     error
+}
+
+// relaunchDoneMsg is sent after a recently killed server was started again.
+type relaunchDoneMsg struct {
+	record ports.RelaunchRecord
+	pid    int
+	log    string
+	err    error
+}
+
+// recordKill saves rec when the kill signal was delivered. Best effort: a failed state write
+// must not turn a successful kill into an error.
+func recordKill(store *ports.RelaunchStore, rec ports.RelaunchRecord, ok bool) {
+	if store != nil && ok {
+		_ = store.Add(rec)
+	}
+}
+
+// restartCmd kills p (or its plan), waits for the port to be released and starts the same
+// command again in the same cwd. The relaunch record is kept if the relaunch fails.
+func (m Model) restartCmd(p ports.Port, plan *ports.KillPlan) tea.Cmd {
+	store := m.Relaunch
+	opts := ports.TerminateOptions{Grace: m.KillGrace, Escalate: m.AutoEscalate}
+	return func() tea.Msg {
+		rec, ok := ports.NewRelaunchRecord(p, ports.RelaunchKilled)
+		if store == nil || !ok {
+			return restartDoneMsg{target: p, err: fmt.Errorf("cannot restart: command or working directory unknown")}
+		}
+		var res ports.TerminateResult
+		if plan != nil {
+			res = plan.Terminate(opts)
+		} else {
+			res = ports.Terminate(p, opts)
+		}
+		recordKill(store, rec, res.OK)
+		if !res.OK || !res.Exited || !res.PortFree {
+			return restartDoneMsg{target: p, result: res}
+		}
+		pid, log, err := store.Relaunch(rec)
+		if err == nil {
+			_ = store.Remove(rec)
+		}
+		return restartDoneMsg{target: p, result: res, pid: pid, log: log, err: err}
+	}
+}
+
+// startRestart runs restartCmd with the progress spinner.
+func (m Model) startRestart() (Model, tea.Cmd) {
+	if m.killTarget == nil || m.killTarget.PID <= 0 || m.Relaunch == nil {
+		return m, nil
+	}
+	if m.killScope != ports.ScopeProcess && m.killPlan == nil {
+		return m, nil // preview still resolving (or failed)
+	}
+	m.killing = true
+	m.killStarted = time.Now()
+	m.killResult = ""
+	return m, tea.Batch(m.restartCmd(*m.killTarget, m.killPlan), spinnerTick())
+}
+
+// loadRelaunchCmd reads the recently killed list off the UI goroutine.
+func (m Model) loadRelaunchCmd() tea.Cmd {
+	store := m.Relaunch
+	return func() tea.Msg {
+		records, err := store.List()
+		return relaunchListMsg{records: records, err: err}
+	}
+}
+
+// relaunchCmd starts a recently killed server again and drops its record on success.
+func (m Model) relaunchCmd(rec ports.RelaunchRecord) tea.Cmd {
+	store := m.Relaunch
+	return func() tea.Msg {
+		pid, log, err := store.Relaunch(rec)
+		if err == nil {
+			_ = store.Remove(rec)
+		}
+		return relaunchDoneMsg{record: rec, pid: pid, log: log, err: err}
+	}
+}
+
+// recordExitedCmd keeps relaunch records for dev servers that disappeared since the last refresh
+// (crashed or stopped outside TAPAS). Servers killed from TAPAS already have a better record.
+func (m Model) recordExitedCmd(before, after []ports.Port) tea.Cmd {
+	if m.Relaunch == nil || before == nil {
+		return nil
+	}
+	alive := make(map[int]bool, len(after))
+	for _, p := range after {
+		alive[p.PID] = true
+	}
+	var records []ports.RelaunchRecord
+	for _, p := range before {
+		if p.PID <= 0 || alive[p.PID] || (p.Framework == "" && p.Environment == "") {
+			continue
+		}
+		if rec, ok := ports.NewRelaunchRecord(p, ports.RelaunchExited); ok {
+			records = append(records, rec)
+			alive[p.PID] = true // IPv4 and IPv6 rows of one process
+		}
+	}
+	if len(records) == 0 {
+		return nil
+	}
+	store := m.Relaunch
+	return func() tea.Msg {
+		for _, rec := range records {
+			_ = store.Add(rec)
+		}
+		return nil
+	}
+}
+
+// updateRelaunchList handles keys in the recently killed list: pick with arrows/Enter or a digit.
+func (m Model) updateRelaunchList(msg tea.KeyMsg) (Model, tea.Cmd) {
+	switch key := msg.String(); key {
+	case "up":
+		if m.relaunchIndex > 0 {
+			m.relaunchIndex--
+		}
+	case "down", "j":
+		if m.relaunchIndex < len(m.relaunchRecords)-1 {
+			m.relaunchIndex++
+		}
+	case "enter":
+		if m.relaunchIndex < len(m.relaunchRecords) {
+			return m, m.relaunchCmd(m.relaunchRecords[m.relaunchIndex])
+		}
+	case "esc", "q", "u":
+		m.showRelaunch = false
+		m.killResult = ""
+	default:
+		if len(key) == 1 && key[0] >= '1' && int(key[0]-'1') < len(m.relaunchRecords) {
+			return m, m.relaunchCmd(m.relaunchRecords[key[0]-'1'])
+		}
+	}
+	return m, nil
+}
+
+// viewRelaunchList renders the recently killed list.
+func (m Model) viewRelaunchList() string {
+	lines := []string{"Recently killed", ""}
+	if len(m.relaunchRecords) == 0 {
+		lines = append(lines, dimStyle.Render("Nothing to relaunch yet. Servers killed from TAPAS show up here."))
+	}
+	for i, r := range m.relaunchRecords {
+		label := r.Project
+		if label == "" {
+			label = r.Process
+		}
+		line := fmt.Sprintf("[%d] :%-5d %-16s %s", i+1, r.Port, truncate(label, 16), truncate(r.CommandLine(), 40))
+		ago := dimStyle.Render(fmt.Sprintf("  %s %s ago", r.Reason, ports.FormatUptime(time.Since(r.At))))
+		if i == m.relaunchIndex {
+			line = accentStyle.Render(line)
+		}
+		lines = append(lines, line+ago)
+	}
+	lines = append(lines, "", "[Enter] Relaunch   [Esc] Close")
+	if m.killResult != "" {
+		lines = append(lines, "", errorStyle.Render(m.killResult))
+	}
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, modalStyle.Render(strings.Join(lines, "\n")))
+}
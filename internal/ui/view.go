@@ -10,7 +10,9 @@ import (
 )
 
 const (
-	statusBar = "[k] Kill   [Enter] Details   [/] Search   [s] Sort   [r] Refresh   [w] Watch   [q] Quit"
+	statusBar = "[k] Kill   [u] Relaunch   [Space] Mark   [a] All   [c] Copy   [x] Export   [Enter] Details   [/] Search   [s] Sort   [r] Refresh   [w] Watch   [o] Stale   [q] Quit"
+	// readOnlyStatusBar drops the kill action (--read-only or policy.read_only).
+	readOnlyStatusBar = "[Space] Mark   [a] All   [c] Copy   [x] Export   [Enter] Details   [/] Search   [s] Sort   [r] Refresh   [w] Watch   [o] Stale   [q] Quit"
 	// Legend under footer: what keys do and what table indicators mean.
 	// Column layout: symbol + Port, Protocol, Process, App, Bind, Conn, Env, Uptime; truncate Project first.
 	colSymbol   = 2 // two cells so ●/○ render reliably and don't get clipped
@@ -54,6 +56,8 @@ var (
 	dockerDotStyle = lipgloss.NewStyle().Foreground(colorMuted)
 	// System: muted dot (port < 1024). Indicator system: ● or · (ASCII).
 	systemDotStyle = lipgloss.NewStyle().Foreground(colorMuted)
+	// Stale: orphaned / deleted cwd / replaced binary. Indicator system: ◌ or ? (ASCII).
+	staleDotStyle = lipgloss.NewStyle().Foreground(colorWarning)
 	// Row semantics: long-run and system use default text + symbol; only system gets muted.
 	longRunStyle = lipgloss.NewStyle() // >24h: symbol "!" only, no color
 	mutedStyle   = lipgloss.NewStyle().Foreground(colorMuted) // system ports <1024
@@ -63,9 +67,18 @@ var (
 
 // View renders the current state. Never executes OS commands.
 func (m Model) View() string {
+	if m.bulkResults != nil {
+		return m.viewBulkResults()
+	}
+	if m.showKillConfirm && len(m.bulkTargets) > 0 {
+		return m.viewBulkConfirm()
+	}
 	if m.showKillConfirm && m.killTarget != nil {
 		return m.viewKillConfirm()
 	}
+	if m.showRelaunch {
+		return m.viewRelaunchList()
+	}
 	if m.showDetails {
 		return m.viewDetails()
 	}
@@ -75,20 +88,188 @@ func (m Model) View() string {
 func (m Model) viewKillConfirm() string {
 	p := m.killTarget
 	canKill := p != nil && p.PID > 0
-	if !canKill {
+	if !canKill && !m.containerModal() {
 		// Impossible: show muted so user sees why nothing will happen
 		body := "Cannot kill this process.\n\n(PID unknown or not permitted.)\n\n[n] Cancel"
 		content := modalStyle.Copy().BorderForeground(lipgloss.Color("#6C757D")).Render(dimStyle.Render(body))
 		return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, content)
 	}
-	body := fmt.Sprintf("Kill port %d (%s)?\n\n[y] Terminate   [k] Force kill   [n] Cancel", p.PortNum, processLabel(p))
+	if m.killing {
+		elapsed := time.Since(m.killStarted)
+		title, waiting := fmt.Sprintf("Killing port %d (%s)...", p.PortNum, ports.ProcessLabel(p)), "waiting for exit and port release"
+		if m.containerModal() {
+			title, waiting = fmt.Sprintf("Container %s (port %d)...", p.DockerContainerName, p.PortNum), "waiting for docker"
+		}
+		body := fmt.Sprintf("%s\n\n%s %s", title, accentStyle.Render(m.spinner()),
+			dimStyle.Render(fmt.Sprintf("%s (%.1fs)", waiting, elapsed.Seconds())))
+		return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, modalStyle.Render(body))
+	}
+	if gate := m.viewPolicyGate(p); gate != "" {
+		return gate
+	}
+	if m.containerModal() {
+		return m.viewContainerActions(p)
+	}
+	if m.signalMenu {
+		return m.viewSignalMenu(p)
+	}
+	restart := ""
+	if m.Relaunch != nil {
+		restart = "   [r] Restart"
+	}
+	body := fmt.Sprintf("Kill port %d (%s)?\n\nScope: %s\n%s\n[y] Terminate   [k] Force kill%s   [s] Signal...   [m] Scope   [n] Cancel",
+		p.PortNum, ports.ProcessLabel(p), accentStyle.Render(m.killScope.String()), m.killPreview(), restart)
+	if p.Stopped {
+		body += "\n\n" + dimStyle.Render("Process is stopped; it may need SIGCONT before it can handle SIGTERM.")
+	}
+	if m.killOutlived {
+		body = fmt.Sprintf("Port %d (%s) did not exit.\n\n[k] Force kill (SIGKILL)   [y] Send %s again   [n] Cancel",
+			p.PortNum, ports.ProcessLabel(p), ports.LookupSignal(m.outlivedSig).Name)
+	}
 	if m.killResult != "" {
 		body += "\n\n" + errorStyle.Render(m.killResult)
 	}
+	if m.elevateSig != 0 {
+		body += "\n" + fmt.Sprintf("[p] Retry %s with sudo", ports.LookupSignal(m.elevateSig).Name)
+	}
 	content := modalStyle.Render(body)
 	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, content)
 }
 
+// viewPolicyGate renders the policy block for a denied or not-yet-confirmed protected target, else "".
+func (m Model) viewPolicyGate(p *ports.Port) string {
+	var body string
+	switch {
+	case m.verdict.Decision == ports.Deny:
+		body = fmt.Sprintf("Port %d (%s) cannot be killed.\n\n%s\n\n[n] Close",
+			p.PortNum, ports.ProcessLabel(p), errorStyle.Render("Denied by policy: "+m.verdict.Reason+"."))
+	case m.verdict.Decision == ports.Confirm && !m.confirmed:
+		head := fmt.Sprintf("Port %d (%s) is protected.", p.PortNum, ports.ProcessLabel(p))
+		if m.verdict.Port != p.PortNum {
+			head = fmt.Sprintf("Killing port %d (%s) also stops protected port %d.", p.PortNum, ports.ProcessLabel(p), m.verdict.Port)
+		}
+		body = fmt.Sprintf("%s\n\n%s\n\nType %d and press Enter to continue: %s\n\n[Esc] Cancel",
+			head, errorStyle.Render("Policy: "+m.verdict.Reason+"."), m.verdict.Port, accentStyle.Render(m.confirmInput+"_"))
+		if m.killResult != "" {
+			body += "\n\n" + errorStyle.Render(m.killResult)
+		}
+	default:
+		return ""
+	}
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, modalStyle.Render(body))
+}
+
+// statusBarText returns the key hints: picker keys in pick mode, no kill actions in read-only mode.
+func (m Model) statusBarText() string {
+	if m.PickMode {
+		return pickStatusBar
+	}
+	if ports.ReadOnly() {
+		return readOnlyStatusBar
+	}
+	return statusBar
+}
+
+// viewContainerActions renders container-native actions; signalling the host PID is opt-in ([h]).
+func (m Model) viewContainerActions(p *ports.Port) string {
+	title := fmt.Sprintf("Port %d is served by container %s", p.PortNum, p.DockerContainerName)
+	if p.DockerImage != "" {
+		title += " (" + p.DockerImage + ")"
+	}
+	body := title + "\n\n" +
+		"[s] Stop   [r] Restart   [p] Pause   [u] Unpause\n" +
+		"[l] Logs   [e] Open shell\n\n" +
+		dimStyle.Render("[h] Signal host PID instead (usually docker-proxy; does not stop the container)") + "\n" +
+		"[n] Cancel"
+	if m.killResult != "" {
+		body += "\n\n" + errorStyle.Render(m.killResult)
+	}
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, modalStyle.Render(body))
+}
+
+// maxPreviewRows caps the scoped-kill preview; the rest is summarized as "... and N more".
+const maxPreviewRows = 10
+
+// killPreview lists every PID and port a group/tree kill will signal, in signal order.
+func (m Model) killPreview() string {
+	if m.killScope == ports.ScopeProcess {
+		return ""
+	}
+	if m.killPlanErr != "" {
+		return errorStyle.Render(m.killPlanErr) + "\n"
+	}
+	if m.killPlan == nil {
+		return dimStyle.Render("Resolving processes...") + "\n"
+	}
+	plan := m.killPlan
+	lines := []string{dimStyle.Render(fmt.Sprintf("Signals %d processes, children first (root: %s, PID %d):", len(plan.Targets), plan.Root.Name, plan.Root.PID))}
+	for i, t := range plan.Targets {
+		if i == maxPreviewRows {
+			lines = append(lines, dimStyle.Render(fmt.Sprintf("  ... and %d more", len(plan.Targets)-maxPreviewRows)))
+			break
+		}
+		line := fmt.Sprintf("  %-7d %s", t.PID, truncate(t.Name, 24))
+		if portNums := plan.Ports[t.PID]; len(portNums) > 0 {
+			line += "  " + accentStyle.Render(formatPorts(portNums))
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
+
+// formatPorts renders ports as ":3000 :3001" (duplicates from IPv4/IPv6 sockets removed).
+func formatPorts(portNums []uint16) string {
+	var parts []string
+	seen := make(map[uint16]bool)
+	for _, n := range portNums {
+		if !seen[n] {
+			seen[n] = true
+			parts = append(parts, fmt.Sprintf(":%d", n))
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// viewSignalMenu renders the signal picker inside the kill modal.
+func (m Model) viewSignalMenu(p *ports.Port) string {
+	var title string
+	if p == nil {
+		title = fmt.Sprintf("Send signal to %s", rowsLabel(len(m.bulkTargets)))
+	} else {
+		title = fmt.Sprintf("Send signal to port %d (%s)", p.PortNum, ports.ProcessLabel(p))
+	}
+	if p != nil && m.killScope != ports.ScopeProcess && m.killPlan != nil {
+		title += fmt.Sprintf(" and %d related processes (%s)", len(m.killPlan.Targets)-1, m.killScope)
+	}
+	lines := []string{title, ""}
+	for i, info := range ports.Signals {
+		line := fmt.Sprintf("[%d] %-8s %s", i+1, info.Name, dimStyle.Render(info.Hint))
+		if i == m.signalIndex {
+			line = accentStyle.Render(fmt.Sprintf("[%d] %-8s ", i+1, info.Name)) + dimStyle.Render(info.Hint)
+		}
+		lines = append(lines, line)
+	}
+	lines = append(lines, "", "[Enter] Send   [Esc] Back")
+	if m.killResult != "" {
+		lines = append(lines, "", errorStyle.Render(m.killResult))
+	}
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, modalStyle.Render(strings.Join(lines, "\n")))
+}
+
+var (
+	spinnerFramesUnicode = []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
+	spinnerFramesASCII   = []string{"|", "/", "-", "\\"}
+)
+
+// spinner returns the current progress frame (ASCII in AsciiMode).
+func (m Model) spinner() string {
+	frames := spinnerFramesUnicode
+	if m.AsciiMode {
+		frames = spinnerFramesASCII
+	}
+	return frames[m.spinnerFrame%len(frames)]
+}
+
 func (m Model) viewDetails() string {
 	p := m.SelectedPort()
 	if p == nil {
@@ -100,7 +281,7 @@ func (m Model) viewDetails() string {
 		"Process:    " + p.Process,
 		"Protocol:   " + p.Protocol,
 		"Working dir: " + p.WorkingDir,
-		"Command:   " + truncate(p.Command, 60),
+		"Command:   " + truncate(m.detailsCommand(p), 60),
 	}
 	if p.Framework != "" {
 		lines = append(lines, "Framework:  "+p.Framework)
@@ -115,7 +296,13 @@ func (m Model) viewDetails() string {
 		lines = append(lines, "Container:  Docker")
 	}
 	if p.BindAddress != "" {
-		lines = append(lines, "Bind:      "+bindLabel(p.BindAddress))
+		lines = append(lines, "Bind:      "+ports.BindLabel(p.BindAddress))
+	}
+	if fix := ports.Remediate(*p); fix != nil {
+		lines = append(lines, "", errorStyle.Render("Public: "+fix.How))
+		for _, l := range wrapWords(fix.Fix, 60) {
+			lines = append(lines, "  "+l)
+		}
 	}
 	if p.ConnectionCount >= 0 {
 		lines = append(lines, fmt.Sprintf("Connections: %d", p.ConnectionCount))
@@ -126,17 +313,76 @@ func (m Model) viewDetails() string {
 	if !p.StartTime.IsZero() {
 		lines = append(lines, "Start time: "+p.StartTime.Format("2006-01-02 15:04:05"))
 	}
-	lines = append(lines, "", "[q] or [Esc] Close")
+	if p.Stopped {
+		lines = append(lines, "State:      stopped (send SIGCONT via [k] > [s] to resume)")
+	}
+	if sec := p.Security; sec != nil {
+		lines = append(lines, "", headerStyle.Render("Security:"))
+		lines = append(lines, "  Capabilities: "+truncate(sec.CapabilitySummary(), 60))
+		if sec.EUID >= 0 {
+			lines = append(lines, fmt.Sprintf("  Effective UID: %d", sec.EUID))
+		}
+		lines = append(lines, "  Seccomp:      "+ports.OrDash(sec.Seccomp))
+		lines = append(lines, "  No new privs: "+yesNo(sec.NoNewPrivs))
+		lines = append(lines, "  Setuid:       "+yesNo(sec.Setuid || sec.Setgid))
+		lines = append(lines, "  LSM label:    "+ports.OrDash(sec.Label))
+		if len(sec.IsolatedNamespaces) > 0 {
+			lines = append(lines, "  Namespaces:   own "+strings.Join(sec.IsolatedNamespaces, ", ")+"; host "+ports.OrDash(strings.Join(sec.SharedNamespaces, ", ")))
+		} else if len(sec.SharedNamespaces) > 0 {
+			lines = append(lines, "  Namespaces:   all shared with host")
+		}
+	}
+	if p.IsStale() {
+		lines = append(lines, "", errorStyle.Render("Stale listener:"))
+		for _, r := range p.StaleReasons {
+			lines = append(lines, "  - "+r.Description())
+		}
+	}
+	if m.historyPort == p.PortNum && len(m.history) > 0 {
+		lines = append(lines, "", headerStyle.Render("History:"))
+		for _, e := range m.history {
+			lines = append(lines, truncate(fmt.Sprintf("  %s  %-10s PID %d  %s", e.Time.Local().Format("01-02 15:04"), e.Event, e.PID, e.Details()), 72))
+		}
+	}
+	closeHint := "[q] or [Esc] Close"
+	if p.SafeCommand() != p.Command {
+		if m.revealCommand {
+			closeHint = "[v] Hide secrets   " + closeHint
+		} else {
+			closeHint = "[v] Reveal command   " + closeHint
+		}
+	}
+	lines = append(lines, "", closeHint)
 	content := modalStyle.Render(strings.Join(lines, "\n"))
 	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, content)
 }
 
+// detailsCommand returns the command for the details modal: redacted unless the user pressed v.
+func (m Model) detailsCommand(p *ports.Port) string {
+	if m.revealCommand {
+		return p.Command
+	}
+	return p.SafeCommand()
+}
+
 func (m Model) viewTable() string {
 	var b strings.Builder
 	title := "TAPAS"
+	if m.PickMode {
+		title += "  (pick)"
+	}
 	if m.WatchEnabled {
 		title += "  (watch " + m.WatchInterval.String() + ")"
 	}
+	if m.staleOnly {
+		title += "  (stale only)"
+	}
+	if ports.ReadOnly() {
+		title += "  (read-only)"
+	}
+	if n := len(m.marked); n > 0 {
+		title += fmt.Sprintf("  (%d marked, Esc to clear)", n)
+	}
 	b.WriteString(titleStyle.Render(title) + "\n\n")
 
 	if m.err != "" {
@@ -155,7 +401,10 @@ func (m Model) viewTable() string {
 		if m.searchQuery != "" {
 			b.WriteString(dimStyle.Render("No matches for \"" + m.searchQuery + "\".") + "\n")
 		}
-		b.WriteString("\n" + statusStyle.Render(statusBar) + "\n" + m.statusLegend())
+		if m.staleOnly {
+			b.WriteString(dimStyle.Render("No stale listeners. Press o to show all.") + "\n")
+		}
+		b.WriteString("\n" + statusStyle.Render(m.statusBarText()) + "\n" + m.statusLegend())
 		return b.String()
 	}
 
@@ -172,11 +421,11 @@ func (m Model) viewTable() string {
 	// Table header: bold, active sort column in accent blue
 	portHdr, protoHdr, processHdr, appHdr, bindHdr, connHdr, envHdr, projectHdr, uptimeHdr := "PORT", "PROTO", "PROCESS", "APP", "BIND", "CONN", "ENV", "PROJECT", "UPTIME"
 	switch m.sortKey {
-	case SortByPort:
+	case ports.SortByPort:
 		portHdr = "PORT \u2191"
-	case SortByUptime:
+	case ports.SortByUptime:
 		uptimeHdr = "UPTIME \u2193" // descending: longest first
-	case SortByProcess:
+	case ports.SortByProcess:
 		processHdr = "PROCESS \u2191"
 	}
 	headerParts := []string{
@@ -193,11 +442,11 @@ func (m Model) viewTable() string {
 	}
 	// Apply accent to active sort column only
 	switch m.sortKey {
-	case SortByPort:
+	case ports.SortByPort:
 		headerParts[1] = accentStyle.Bold(true).Render(fmt.Sprintf("%-*s", colPort, truncate(portHdr, colPort)))
-	case SortByUptime:
+	case ports.SortByUptime:
 		headerParts[9] = accentStyle.Bold(true).Render(fmt.Sprintf("%-*s", colUptime, truncate(uptimeHdr, colUptime)))
-	case SortByProcess:
+	case ports.SortByProcess:
 		headerParts[3] = accentStyle.Bold(true).Render(fmt.Sprintf("%-*s", colProcess, truncate(processHdr, colProcess)))
 	}
 	header := strings.Join(headerParts, " ") + "\n"
@@ -219,12 +468,22 @@ func (m Model) viewTable() string {
 			// Pad to colSymbol width so the indicator column is stable and ●/○ don't get clipped
 			firstPart = style.Render(firstCol) + " "
 		}
+		if m.marked[rowKey(p)] {
+			// Second cell of the symbol column carries the mark.
+			firstPart = strings.TrimSuffix(firstPart, " ") + accentStyle.Render(markSymbol(m.AsciiMode))
+			if firstCol == " " {
+				firstPart = " " + accentStyle.Render(markSymbol(m.AsciiMode))
+			}
+		}
 		middlePart := rowLineMiddle(&p, projectCol)
 		var publicPart string
 		if pub := publicIndicator(&p, m.AsciiMode); pub != "" {
 			publicPart = " " + publicDotStyle.Render(pub)
 		} else {
-			publicPart = " "
+			publicPart = "  "
+		}
+		if stale := staleIndicator(&p, m.AsciiMode); stale != "" {
+			publicPart += staleDotStyle.Render(stale)
 		}
 		rowStyle := rowStyleForKind(kind)
 		if i == m.selected {
@@ -237,25 +496,26 @@ func (m Model) viewTable() string {
 	b.WriteString("\n")
 	if m.searchMode {
 		b.WriteString(accentStyle.Render("/ ") + statusStyle.Render(m.searchQuery) + dimStyle.Render("_") + "\n")
-		b.WriteString(dimStyle.Render("Esc to clear search") + "\n")
+		b.WriteString(dimStyle.Render("Esc to clear search   Filters: is:stale is:stopped is:public is:root is:setuid cap:net_bind_service ns:net") + "\n")
 	} else {
-		b.WriteString(statusStyle.Render(statusBar) + "\n" + m.statusLegend())
+		b.WriteString(statusStyle.Render(m.statusBarText()) + "\n" + m.statusLegend())
 	}
 	return b.String()
 }
 
 // statusLegend returns the footer legend (what table indicators mean). Muted style; symbols use indicator colors.
 func (m Model) statusLegend() string {
-	pubSym, dockSym, sysSym := indicatorPublicUnicode, indicatorDockerUnicode, indicatorSystemUnicode
+	pubSym, dockSym, sysSym, staleSym := indicatorPublicUnicode, indicatorDockerUnicode, indicatorSystemUnicode, indicatorStaleUnicode
 	if m.AsciiMode {
-		pubSym, dockSym, sysSym = indicatorPublicASCII, indicatorDockerASCII, indicatorSystemASCII
+		pubSym, dockSym, sysSym, staleSym = indicatorPublicASCII, indicatorDockerASCII, indicatorSystemASCII, indicatorStaleASCII
 	}
 	return publicDotStyle.Render(pubSym) + dimStyle.Render(" public port   ") +
 		dockerDotStyle.Render(dockSym) + dimStyle.Render(" Docker   ") +
-		systemDotStyle.Render(sysSym) + dimStyle.Render(" system")
+		systemDotStyle.Render(sysSym) + dimStyle.Render(" system   ") +
+		staleDotStyle.Render(staleSym) + dimStyle.Render(" stale")
 }
 
-// Indicator system: Public ●/!, Docker ○/-, System ●/· (muted), Local empty. Shape-first, color-second.
+// Indicator system: Public ●/!, Docker ○/-, System ●/· (muted), Stale ◌/?, Local empty. Shape-first, color-second.
 
 const (
 	indicatorPublicUnicode = "\u25cf" // ●
@@ -264,6 +524,8 @@ const (
 	indicatorDockerASCII   = "-"
 	indicatorSystemUnicode = "\u25cf" // ● (muted, same shape as public)
 	indicatorSystemASCII   = "\u00b7" // · middle dot
+	indicatorStaleUnicode  = "\u25cc" // ◌ dotted circle: nobody owns it
+	indicatorStaleASCII    = "?"
 )
 
 // firstColumnIndicator returns the first-column char and which style to use (Docker ○, System ●, or space).
@@ -286,7 +548,7 @@ func firstColumnIndicator(p *ports.Port, ascii bool) (char string, isDocker, isS
 
 // publicIndicator returns the right-column indicator for public bind (● or ! in ASCII); empty for local.
 func publicIndicator(p *ports.Port, ascii bool) string {
-	if !isPublicBind(p.BindAddress) {
+	if !ports.IsPublicBind(p.BindAddress) {
 		return ""
 	}
 	if ascii {
@@ -295,6 +557,25 @@ func publicIndicator(p *ports.Port, ascii bool) string {
 	return indicatorPublicUnicode
 }
 
+// markSymbol is shown next to marked rows (multi-select).
+func markSymbol(ascii bool) string {
+	if ascii {
+		return "*"
+	}
+	return "✓"
+}
+
+// staleIndicator returns the stale marker shown after the public column; empty when healthy.
+func staleIndicator(p *ports.Port, ascii bool) string {
+	if !p.IsStale() {
+		return ""
+	}
+	if ascii {
+		return indicatorStaleASCII
+	}
+	return indicatorStaleUnicode
+}
+
 // rowKind is the semantic category of a port row (for style only; indicators are separate).
 type rowKind int
 
@@ -338,33 +619,6 @@ func rowStyleForKind(k rowKind) lipgloss.Style {
 	}
 }
 
-// projectLabel returns the PROJECT column text: "dili (Next.js)" or for Docker "pulso-api (Docker)".
-func projectLabel(p *ports.Port) string {
-	base := ""
-	if p.DockerContainerName != "" {
-		// Docker: use container name as project context (host cwd is meaningless)
-		base = p.DockerContainerName
-	} else {
-		base = p.ProjectDisplayName
-		if base == "" {
-			base = p.Project()
-		}
-	}
-	if base == "" || base == "/" {
-		base = "—"
-	}
-	if base == "—" {
-		return base
-	}
-	if p.DockerContainerName != "" {
-		return base + " (Docker)"
-	}
-	if p.Framework != "" {
-		return base + " (" + p.Framework + ")"
-	}
-	return base
-}
-
 // connLabel returns the connection count for the CONN column ("0", "4", etc.).
 func connLabel(count int) string {
 	if count <= 0 {
@@ -381,103 +635,47 @@ func envLabel(env string) string {
 	return env
 }
 
-// isPublicBind reports whether the bind address exposes the port publicly (all interfaces).
-// IPv4: 0.0.0.0, *; IPv6: ::, [::].
-func isPublicBind(addr string) bool {
-	switch addr {
-	case "0.0.0.0", "*", "", "::", "[::]":
-		return true
-	default:
-		return false
+func yesNo(b bool) string {
+	if b {
+		return "yes"
 	}
-}
-
-// bindLabel returns LOCAL, PUBLIC, or the bind address for security awareness.
-func bindLabel(addr string) string {
-	switch addr {
-	case "127.0.0.1", "::1":
-		return "LOCAL"
-	case "0.0.0.0", "*", "", "::", "[::]":
-		return "PUBLIC"
-	default:
-		return addr
-	}
-}
-
-// processLabel returns the process column text: Docker, "PostgreSQL (local)", "Framework (process)", or process name.
-func processLabel(p *ports.Port) string {
-	if p.DockerContainerName != "" {
-		s := "Docker → " + p.DockerContainerName
-		if p.DockerImage != "" {
-			s += " (" + p.DockerImage + ")"
-		}
-		return s
-	}
-	if db := ports.DatabaseProductName(p.PortNum); db != "" {
-		return db + " (" + bindLabel(p.BindAddress) + ")"
-	}
-	if p.Framework != "" {
-		if p.Process != "" && p.Process != "—" {
-			return p.Framework + " (" + p.Process + ")"
-		}
-		return p.Framework
-	}
-	if p.Process == "" {
-		return "—"
-	}
-	return p.Process
-}
-
-// appBadge returns the framework badge + Docker indicator + common app name for the APP column.
-func appBadge(p *ports.Port) string {
-	var badge string
-	if p.DockerContainerName != "" {
-		badge = "Docker"
-	} else if p.Framework != "" {
-		badge = p.Framework
-		if p.InDocker {
-			badge += " D"
-		}
-	} else if app := ports.AppName(p.PortNum, p.Process); app != "" {
-		badge = app
-		if p.InDocker {
-			badge += " D"
-		}
-	} else {
-		badge = "—"
-		if p.InDocker {
-			badge += " D"
-		}
-	}
-	return truncate(badge, colApp)
+	return "no"
 }
 
 // rowLineMiddle returns the row content without the first column (port through uptime). Used so we can apply row style only to this part and keep indicator colors intact.
 func rowLineMiddle(p *ports.Port, projectCol int) string {
-	uptime := formatUptime(p.Uptime())
-	project := truncate(projectLabel(p), projectCol)
+	uptime := ports.FormatUptime(p.Uptime())
+	project := truncate(ports.ProjectLabel(p), projectCol)
 	proto := truncate(strings.ToUpper(p.Protocol), colProtocol)
 	if proto == "" {
 		proto = "—"
 	}
+	process := ports.ProcessLabel(p)
+	if p.Stopped {
+		process = "[stopped] " + process
+	}
 	// Leading space aligns with the gap between symbol column and port in the header.
-	return " " + fmt.Sprintf("%-*d %-*s %-*s %-*s %-*s %-*s %-*s %-*s %-*s", colPort, p.PortNum, colProtocol, proto, colProcess, truncate(processLabel(p), colProcess), colApp, appBadge(p), colBind, truncate(bindLabel(p.BindAddress), colBind), colConn, connLabel(p.ConnectionCount), colEnv, truncate(envLabel(p.Environment), colEnv), projectCol, project, colUptime, uptime)
+	return " " + fmt.Sprintf("%-*d %-*s %-*s %-*s %-*s %-*s %-*s %-*s %-*s", colPort, p.PortNum, colProtocol, proto, colProcess, truncate(process, colProcess), colApp, truncate(ports.AppBadge(p), colApp), colBind, truncate(ports.BindLabel(p.BindAddress), colBind), colConn, connLabel(p.ConnectionCount), colEnv, truncate(envLabel(p.Environment), colEnv), projectCol, project, colUptime, uptime)
 }
 
-func formatUptime(d time.Duration) string {
-	if d <= 0 {
-		return "—"
-	}
-	if d < time.Minute {
-		return fmt.Sprintf("%ds", int(d.Seconds()))
-	}
-	if d < time.Hour {
-		return fmt.Sprintf("%dm", int(d.Minutes()))
+// wrapWords breaks s into lines of at most width bytes at spaces; longer words get their own line.
+func wrapWords(s string, width int) []string {
+	var lines []string
+	line := ""
+	for _, w := range strings.Fields(s) {
+		if line != "" && len(line)+1+len(w) > width {
+			lines = append(lines, line)
+			line = ""
+		}
+		if line != "" {
+			line += " "
+		}
+		line += w
 	}
-	if d < 24*time.Hour {
-		return fmt.Sprintf("%dh", int(d.Hours()))
+	if line != "" {
+		lines = append(lines, line)
 	}
-	return fmt.Sprintf("%dd", int(d.Hours()/24))
+	return lines
 }
 
 func truncate(s string, maxLen int) string {
@@ -4,21 +4,94 @@ import (
 	"flag"
 	"fmt"
 	"os"
+	"strconv"
+	"time"
 
 	"github.com/charmbracelet/bubbletea"
+	"github.com/javiercepeda/tapas/internal/cli"
+	"github.com/javiercepeda/tapas/internal/config"
 	"github.com/javiercepeda/tapas/internal/ports"
 	"github.com/javiercepeda/tapas/internal/ui"
 )
 
 func main() {
+	cfg, err := config.Load()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "config:", err)
+		os.Exit(1)
+	}
+	// --read-only (or TAPAS_READ_ONLY) goes before the command so every subcommand honors it.
+	args := os.Args[1:]
+	globalReadOnly, _ := strconv.ParseBool(os.Getenv("TAPAS_READ_ONLY"))
+	for len(args) > 0 && (args[0] == "--read-only" || args[0] == "-read-only") {
+		args, globalReadOnly = args[1:], true
+	}
+	if len(args) > 0 {
+		if cmd, ok := cli.Lookup(args[0]); ok {
+			if err := configure(cfg, globalReadOnly); err != nil {
+				fmt.Fprintln(os.Stderr, "config:", err)
+				os.Exit(1)
+			}
+			env := cli.Env{Config: cfg, StateDir: config.StateDir(), Lister: ports.DefaultLister()}
+			os.Exit(cmd.Run(env, args[1:]))
+		}
+	}
+
+	flag.Usage = usage
 	ascii := flag.Bool("ascii", false, "Use ASCII indicators only (! public, - Docker)")
+	readOnly := flag.Bool("read-only", false, "Disable all kill, signal and container actions (also before a command, or set TAPAS_READ_ONLY)")
 	flag.Parse()
+	if flag.NArg() > 0 {
+		fmt.Fprintf(os.Stderr, "tapas: unknown command %q\n\n", flag.Arg(0))
+		usage()
+		os.Exit(2)
+	}
+	if err := configure(cfg, *readOnly || globalReadOnly); err != nil {
+		fmt.Fprintln(os.Stderr, "config:", err)
+		os.Exit(1)
+	}
 
 	lister := ports.DefaultLister()
 	m := ui.NewModel(lister, *ascii)
+	m.KillGrace = time.Duration(cfg.Kill.GracePeriod)
+	m.AutoEscalate = cfg.Kill.AutoEscalate
+	if dir := config.StateDir(); dir != "" {
+		m.Relaunch = ports.NewRelaunchStore(dir)
+		m.Activity = ports.NewActivityStore(dir)
+	}
 	p := tea.NewProgram(m, tea.WithAltScreen())
 	if _, err := p.Run(); err != nil {
 		fmt.Fprintln(os.Stderr, err)
 		os.Exit(1)
 	}
 }
+
+// configure installs the process-wide settings from the config file: redaction, policy, audit log and history.
+func configure(cfg config.Config, readOnly bool) error {
+	if err := ports.SetRedactPatterns(cfg.RedactPatterns); err != nil {
+		return err
+	}
+	policy := ports.Policy{
+		ReadOnly:  readOnly || cfg.Policy.ReadOnly,
+		Protected: ports.PolicyRules(cfg.Policy.Protected),
+		Deny:      ports.PolicyRules(cfg.Policy.Deny),
+	}
+	if err := ports.ValidatePolicy(policy); err != nil {
+		return err
+	}
+	ports.SetPolicy(policy)
+	ports.SetAuditLog(cfg.AuditPath())
+	ports.SetHistoryLog(cfg.HistoryPath(), int64(cfg.History.MaxSizeKB)*1024, cfg.History.Files)
+	return nil
+}
+
+func usage() {
+	out := flag.CommandLine.Output()
+	fmt.Fprintln(out, "Usage: tapas [flags]           start the interactive UI")
+	fmt.Fprintln(out, "       tapas [--read-only] <command> [args]")
+	fmt.Fprintln(out, "                               run a command (tapas <command> -h for its flags)")
+	fmt.Fprintln(out, "\nCommands:")
+	cli.PrintCommands(out)
+	fmt.Fprintln(out, "\nFlags:")
+	flag.PrintDefaults()
+}